@@ -0,0 +1,219 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/coredns/coredns/request"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// auditStdout writes the audit log to the standard output
+	auditStdout = "stdout"
+
+	// defaultAuditSampleRate is the default fraction of the chaos decisions of DNS requests written to the audit log
+	defaultAuditSampleRate = 0.1
+
+	// the decisions of the DNS requests matched by an experiment in the audit log and the events
+	decisionInjected = "injected"
+	decisionDryRun   = "dry_run"
+	decisionPaused   = "paused"
+)
+
+// auditCallerKey is the context key of the caller of the changes not made through the grpc service
+type auditCallerKey struct{}
+
+// withAuditCaller returns a context which changes are recorded as made by caller
+func withAuditCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, auditCallerKey{}, caller)
+}
+
+// auditCaller identifies who made a change
+type auditCaller struct {
+	// Name is set for the changes not made through the grpc service, for example by the DNSChaos watcher
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+	// Subject is the subject of the client certificate when mTLS is enabled
+	Subject   string `json:"subject,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// auditEntry is a line of the audit log, Type is "control" for the changes of experiments and
+// "query" for the chaos decisions of DNS requests
+type auditEntry struct {
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"`
+	Experiment string    `json:"experiment,omitempty"`
+
+	// fields of the changes of experiments
+	Operation string          `json:"operation,omitempty"`
+	Caller    *auditCaller    `json:"caller,omitempty"`
+	Request   json.RawMessage `json:"request,omitempty"`
+	Code      string          `json:"code,omitempty"`
+	Error     string          `json:"error,omitempty"`
+
+	// fields of the chaos decisions of DNS requests
+	Pod      string `json:"pod,omitempty"`
+	ClientIP string `json:"client_ip,omitempty"`
+	Qname    string `json:"qname,omitempty"`
+	Qtype    string `json:"qtype,omitempty"`
+	Action   string `json:"action,omitempty"`
+	Decision string `json:"decision,omitempty"`
+}
+
+// auditLogger writes the audit log as JSON lines, a nil auditLogger writes nothing
+type auditLogger struct {
+	output     string
+	sampleRate float64
+
+	sync.Mutex
+	w       io.WriteCloser
+	encoder *json.Encoder
+	rand    *rand.Rand
+}
+
+func newAuditLogger(output string, sampleRate float64) *auditLogger {
+	return &auditLogger{
+		output:     output,
+		sampleRate: sampleRate,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// open opens the output of the audit log, the file is appended to
+func (l *auditLogger) open() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.output == auditStdout {
+		l.w = os.Stdout
+	} else {
+		f, err := os.OpenFile(l.output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		l.w = f
+	}
+	l.encoder = json.NewEncoder(l.w)
+	return nil
+}
+
+func (l *auditLogger) close() error {
+	l.Lock()
+	defer l.Unlock()
+
+	w := l.w
+	l.w, l.encoder = nil, nil
+	if w == nil || l.output == auditStdout {
+		return nil
+	}
+	return w.Close()
+}
+
+func (l *auditLogger) write(entry *auditEntry) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.encoder == nil {
+		return
+	}
+	if err := l.encoder.Encode(entry); err != nil {
+		log.Errorf("fail to write audit log: %v", err)
+	}
+}
+
+// control records a change of an experiment with the caller and the result
+func (l *auditLogger) control(ctx context.Context, operation, experiment string, req proto.Message, err error) {
+	if l == nil {
+		return
+	}
+
+	entry := &auditEntry{
+		Time:       time.Now(),
+		Type:       "control",
+		Experiment: experiment,
+		Operation:  operation,
+		Caller:     callerOf(ctx),
+		Code:       status.Code(err).String(),
+	}
+	if err != nil {
+		entry.Error = status.Convert(err).Message()
+	}
+	if req != nil {
+		content, marshalErr := (&jsonpb.Marshaler{OrigName: true}).MarshalToString(req)
+		if marshalErr != nil {
+			log.Warningf("fail to marshal request of %s in audit log: %v", operation, marshalErr)
+		} else {
+			entry.Request = json.RawMessage(content)
+		}
+	}
+
+	l.write(entry)
+}
+
+// query records the chaos decision of a DNS request, only a sample of them is written
+func (l *auditLogger) query(state request.Request, podInfo *PodInfo, decision string) {
+	if l == nil || !l.sampled() {
+		return
+	}
+
+	l.write(&auditEntry{
+		Time:       time.Now(),
+		Type:       "query",
+		Experiment: experimentLabel(podInfo),
+		Pod:        podInfo.Namespace + "/" + podInfo.Name,
+		ClientIP:   state.IP(),
+		Qname:      state.Name(),
+		Qtype:      state.Type(),
+		Action:     podInfo.Action,
+		Decision:   decision,
+	})
+}
+
+func (l *auditLogger) sampled() bool {
+	if l.sampleRate >= 1 {
+		return true
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	return l.rand.Float64() < l.sampleRate
+}
+
+// callerOf returns the identity of the caller in the context
+func callerOf(ctx context.Context) *auditCaller {
+	caller := &auditCaller{}
+	if name, ok := ctx.Value(auditCallerKey{}).(string); ok {
+		caller.Name = name
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			caller.Address = p.Addr.String()
+		}
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) != 0 {
+			caller.Subject = tlsInfo.State.PeerCertificates[0].Subject.String()
+		}
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if userAgent := md.Get("user-agent"); len(userAgent) != 0 {
+			caller.UserAgent = userAgent[0]
+		}
+	}
+
+	return caller
+}
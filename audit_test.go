@@ -0,0 +1,98 @@
+package kubernetes
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestAuditLog(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "audit.log")
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionError})
+	k.audit = newAuditLogger(output, 1)
+	if err := k.audit.open(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.9"), Port: 4321}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", "chaos-daemon"))
+
+	if _, err := k.SetDNSChaos(ctx, &pb.SetDNSChaosRequest{Name: "invalid", Action: "delay"}); err == nil {
+		t.Fatalf("Expected error, got none")
+	}
+	m := new(dns.Msg)
+	m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+	k.ServeDNS(context.TODO(), dnstest.NewRecorder(&test.ResponseWriter{}), m)
+	if _, err := k.CancelDNSChaos(withAuditCaller(context.Background(), "DNSChaos testns/chaos"), &pb.CancelDNSChaosRequest{Name: "chaos"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := k.audit.close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	f, err := os.Open(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected JSON line, got %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	set := entries[0]
+	if set.Type != "control" || set.Operation != "SetDNSChaos" || set.Code != "InvalidArgument" || set.Error == "" {
+		t.Errorf("Expected failed SetDNSChaos entry, got %+v", set)
+	}
+	if set.Caller == nil || set.Caller.Address != "10.0.0.9:4321" || set.Caller.UserAgent != "chaos-daemon" {
+		t.Errorf("Expected caller to be recorded, got %+v", set.Caller)
+	}
+	if set.Experiment != "invalid" || len(set.Request) == 0 {
+		t.Errorf("Expected request of experiment invalid to be recorded, got %+v", set)
+	}
+
+	query := entries[1]
+	if query.Type != "query" || query.Experiment != "chaos" || query.Pod != "testns/client" || query.ClientIP != chaosClientIP ||
+		query.Qname != "svc1.testns.svc.cluster.local." || query.Qtype != "A" || query.Action != ActionError || query.Decision != decisionInjected {
+		t.Errorf("Expected injected query entry, got %+v", query)
+	}
+
+	cancel := entries[2]
+	if cancel.Operation != "CancelDNSChaos" || cancel.Code != "OK" || cancel.Caller == nil || cancel.Caller.Name != "DNSChaos testns/chaos" {
+		t.Errorf("Expected CancelDNSChaos entry by the watcher, got %+v", cancel)
+	}
+}
+
+func TestAuditLogSampling(t *testing.T) {
+	l := newAuditLogger(auditStdout, 0)
+	for i := 0; i < 100; i++ {
+		if l.sampled() {
+			t.Fatalf("Expected nothing to be sampled with rate 0")
+		}
+	}
+
+	var nilLogger *auditLogger
+	// a nil logger writes nothing
+	nilLogger.control(context.Background(), "SetDNSChaos", "chaos", nil, nil)
+}
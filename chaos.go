@@ -3,11 +3,19 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"math/rand"
 	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin"
 	"github.com/coredns/coredns/request"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/miekg/dns"
 	selector "github.com/pingcap/tidb-tools/pkg/table-rule-selector"
 	api "k8s.io/api/core/v1"
@@ -26,8 +34,330 @@ const (
 	ActionError = "error"
 	// ActionRandom means return random IP for DNS request
 	ActionRandom = "random"
+	// ActionCNAME means return a CNAME to the target for DNS request
+	ActionCNAME = "cname"
+	// ActionCNAMELoop means return a CNAME loop for DNS request
+	ActionCNAMELoop = "cname_loop"
+	// ActionCNAMEChain means return a long CNAME chain for DNS request
+	ActionCNAMEChain = "cname_chain"
+	// ActionTruncate means return a truncated answer for DNS request over UDP
+	ActionTruncate = "truncate"
+	// ActionMalformed means return a malformed answer for DNS request
+	ActionMalformed = "malformed"
+	// ActionMutate means return the perturbed real answer for DNS request
+	ActionMutate = "mutate"
+	// ActionPartial means return the real answer with some of the endpoints dropped for DNS request
+	ActionPartial = "partial"
+	// ActionHotspot means return the real answer pinned to a single endpoint for DNS request
+	ActionHotspot = "hotspot"
+	// ActionMisroute means return the records of another service for DNS request
+	ActionMisroute = "misroute"
+	// ActionTTL means return the real answer with the TTLs rewritten for DNS request
+	ActionTTL = "ttl"
+
+	// RandomPerQuery means the random action returns a new random IP for every DNS request
+	RandomPerQuery = "query"
+	// RandomPerName means the random action returns the same random IP for a name during the experiment
+	RandomPerName = "name"
+	// RandomPerPod means the random action returns the same random IP for a name and a client pod during the experiment
+	RandomPerPod = "pod"
+	// ProtocolUDP means chaos only works on the DNS requests over UDP
+	ProtocolUDP = "udp"
+	// ProtocolTCP means chaos only works on the DNS requests over TCP
+	ProtocolTCP = "tcp"
+
+	// defaultChaosTTL is the TTL of the records in the chaos answers if the experiment doesn't set it
+	defaultChaosTTL = 10
+	// maxTTL is the max TTL of the records, the TTLs with the most significant bit set are treated as
+	// zero, see RFC 2181
+	maxTTL = 1<<31 - 1
 )
 
+// actions are the supported chaos actions
+var actions = []string{ActionError, ActionRandom, ActionCNAME, ActionCNAMELoop, ActionCNAMEChain, ActionTruncate, ActionMalformed, ActionMutate, ActionPartial, ActionHotspot, ActionMisroute, ActionTTL}
+
+// isValidAction returns whether the action is supported
+func isValidAction(action string) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidScope returns whether the scope is supported, an empty scope is treated as ScopeAll
+func isValidScope(scope string) bool {
+	switch scope {
+	case "", ScopeInner, ScopeOuter, ScopeAll:
+		return true
+	}
+	return false
+}
+
+// isValidRandomMode returns whether the random mode is supported, an empty mode is treated as RandomPerQuery
+func isValidRandomMode(mode string) bool {
+	switch mode {
+	case "", RandomPerQuery, RandomPerName, RandomPerPod:
+		return true
+	}
+	return false
+}
+
+// isValidProtocol returns whether the protocol is supported, an empty protocol means both
+func isValidProtocol(protocol string) bool {
+	switch protocol {
+	case "", ProtocolUDP, ProtocolTCP:
+		return true
+	}
+	return false
+}
+
+// parseQTypes converts the names of query types, such as "AAAA", to their values
+func parseQTypes(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	qtypes := make([]uint16, 0, len(names))
+	for _, name := range names {
+		qtype, ok := dns.StringToType[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown query type %q", name)
+		}
+		qtypes = append(qtypes, qtype)
+	}
+	return qtypes, nil
+}
+
+func hasQType(qtypes []uint16, qtype uint16) bool {
+	for _, t := range qtypes {
+		if t == qtype {
+			return true
+		}
+	}
+	return false
+}
+
+// chaosLookupKey is the context key of the lookups made while injecting chaos
+type chaosLookupKey struct{}
+
+// withChaosLookup marks a lookup made while injecting chaos, for example of a CNAME target. The lookup
+// is served by the whole server with the address of the client, chaos must not be injected into it again.
+func withChaosLookup(ctx context.Context) context.Context {
+	return context.WithValue(ctx, chaosLookupKey{}, true)
+}
+
+// isChaosLookup returns whether the request is a lookup made while injecting chaos
+func isChaosLookup(ctx context.Context) bool {
+	lookup, _ := ctx.Value(chaosLookupKey{}).(bool)
+	return lookup
+}
+
+// chaosState saves the experiments and the pods which chaos is applied to, it is handed over
+// to the new instance when Corefile is reloaded
+type chaosState struct {
+	sync.RWMutex
+	chaosMap map[string]*Experiment
+	// namespace -> pod_name -> pod info
+	podMap map[string]map[string]*PodInfo
+
+	ipPodMap map[string]*PodInfo
+
+	// events fans out the chaos events to the subscribers of WatchDNSChaosEvents
+	events *eventBroker
+
+	// resolveFailures saves the pods of experiments whose IP fails to be refreshed, it has its own
+	// lock because the failures are recorded while serving DNS requests
+	resolveLock     sync.Mutex
+	resolveFailures map[*PodInfo]*resolveFailure
+
+	// paused is set by PauseAll, chaos isn't injected while it is set. It is read without the lock
+	// while serving DNS requests, the reason and time are guarded by the lock.
+	paused      int32
+	pauseReason string
+	pauseTime   time.Time
+}
+
+func newChaosState() *chaosState {
+	return &chaosState{
+		chaosMap: make(map[string]*Experiment),
+		podMap:   make(map[string]map[string]*PodInfo),
+		ipPodMap: make(map[string]*PodInfo),
+		events:   newEventBroker(),
+
+		resolveFailures: make(map[*PodInfo]*resolveFailure),
+	}
+}
+
+const (
+	// podRetryInterval is the interval of retrying to refresh the IP of a pod after the first failure,
+	// it is doubled after every failure up to maxPodRetryInterval
+	podRetryInterval    = 5 * time.Second
+	maxPodRetryInterval = 5 * time.Minute
+)
+
+// resolveFailure saves the failures of refreshing the IP of a pod
+type resolveFailure struct {
+	err   error
+	count int
+	// nextRetry is the time after which the health check retries the pod
+	nextRetry time.Time
+}
+
+// Experiment saves the information of a chaos experiment
+type Experiment struct {
+	// Hits is the count of DNS requests which chaos is injected into,
+	// it is placed first to be 8-byte aligned for atomic operations
+	Hits int64
+	// DryRunHits is the count of DNS requests which chaos would be injected into in dry run
+	DryRunHits int64
+	// Matched is the count of DNS requests matched by the experiment, including the ones served
+	// with the real answers in dry run or while chaos is paused
+	Matched int64
+
+	// Name never changes, so it can be read without the lock
+	Name       string
+	Request    *pb.SetDNSChaosRequest
+	CreateTime time.Time
+	// Generation is increased every time the experiment is set or updated
+	Generation int64
+
+	// statsLock guards the statistics of the injections besides the counters
+	statsLock sync.Mutex
+	// injectedByAction is the count of injections by action
+	injectedByAction map[string]int64
+	// podHits is the count of injections by client pod
+	podHits            map[podKey]int64
+	firstInjectionTime time.Time
+	lastInjectionTime  time.Time
+	// series are the labels of the chaos metrics written for the experiment, they are deleted
+	// when the experiment is canceled
+	series map[metricSeries]struct{}
+}
+
+// match records a DNS request matched by the experiment
+func (e *Experiment) match() {
+	if e == nil {
+		return
+	}
+	atomic.AddInt64(&e.Matched, 1)
+}
+
+// hit records a DNS request from the pod which chaos is injected into
+func (e *Experiment) hit(podInfo *PodInfo) {
+	if e == nil {
+		return
+	}
+	atomic.AddInt64(&e.Hits, 1)
+
+	now := time.Now()
+	e.statsLock.Lock()
+	defer e.statsLock.Unlock()
+
+	if e.injectedByAction == nil {
+		e.injectedByAction = make(map[string]int64)
+		e.podHits = make(map[podKey]int64)
+	}
+	e.injectedByAction[podInfo.Action]++
+	e.podHits[podKey{podInfo.Namespace, podInfo.Name}]++
+	if e.firstInjectionTime.IsZero() {
+		e.firstInjectionTime = now
+	}
+	e.lastInjectionTime = now
+}
+
+// addSeries records the labels of the chaos metrics written for the experiment
+func (e *Experiment) addSeries(series metricSeries) {
+	if e == nil {
+		return
+	}
+
+	e.statsLock.Lock()
+	defer e.statsLock.Unlock()
+
+	if e.series == nil {
+		e.series = make(map[metricSeries]struct{})
+	}
+	e.series[series] = struct{}{}
+}
+
+// metricSeries returns the labels of the chaos metrics written for the experiment
+func (e *Experiment) metricSeries() []metricSeries {
+	e.statsLock.Lock()
+	defer e.statsLock.Unlock()
+
+	series := make([]metricSeries, 0, len(e.series))
+	for s := range e.series {
+		series = append(series, s)
+	}
+	return series
+}
+
+// HitCount returns the count of DNS requests which chaos is injected into
+func (e *Experiment) HitCount() int64 {
+	return atomic.LoadInt64(&e.Hits)
+}
+
+// dryRunHit records a DNS request which chaos would be injected into
+func (e *Experiment) dryRunHit() {
+	if e == nil {
+		return
+	}
+	atomic.AddInt64(&e.DryRunHits, 1)
+}
+
+// DryRunHitCount returns the count of DNS requests which chaos would be injected into in dry run
+func (e *Experiment) DryRunHitCount() int64 {
+	return atomic.LoadInt64(&e.DryRunHits)
+}
+
+// MatchCount returns the count of DNS requests matched by the experiment
+func (e *Experiment) MatchCount() int64 {
+	return atomic.LoadInt64(&e.Matched)
+}
+
+// stats returns a snapshot of the statistics of the experiment
+func (e *Experiment) stats() *pb.DNSChaosStats {
+	s := &pb.DNSChaosStats{
+		Name:             e.Name,
+		Matched:          e.MatchCount(),
+		Injected:         e.HitCount(),
+		InjectedByAction: make(map[string]int64),
+		DryRunHits:       e.DryRunHitCount(),
+	}
+
+	e.statsLock.Lock()
+	defer e.statsLock.Unlock()
+
+	for action, count := range e.injectedByAction {
+		s.InjectedByAction[action] = count
+	}
+
+	for pod, hits := range e.podHits {
+		s.PodHits = append(s.PodHits, &pb.PodHits{Namespace: pod.namespace, Name: pod.name, Hits: hits})
+	}
+	sort.Slice(s.PodHits, func(i, j int) bool {
+		if s.PodHits[i].Namespace != s.PodHits[j].Namespace {
+			return s.PodHits[i].Namespace < s.PodHits[j].Namespace
+		}
+		return s.PodHits[i].Name < s.PodHits[j].Name
+	})
+
+	if !e.firstInjectionTime.IsZero() {
+		s.FirstInjectionTime, _ = ptypes.TimestampProto(e.firstInjectionTime)
+		s.LastInjectionTime, _ = ptypes.TimestampProto(e.lastInjectionTime)
+	}
+
+	return s
+}
+
+// podKey identifies a pod
+type podKey struct {
+	namespace string
+	name      string
+}
+
 // PodInfo saves some information for pod
 type PodInfo struct {
 	Namespace      string
@@ -37,6 +367,53 @@ type PodInfo struct {
 	Selector       selector.Selector
 	IP             string
 	LastUpdateTime time.Time
+	// DryRun means the real answers are served, chaos is only counted and logged
+	DryRun bool
+	// RandomMode means how the random action chooses the IP
+	RandomMode string
+	// CNAMETarget and CNAMEDepth are the target and the depth of the CNAME actions
+	CNAMETarget string
+	CNAMEDepth  int
+	// Protocol restricts chaos to the DNS requests over UDP or TCP, empty means both
+	Protocol string
+	// QTypes restricts chaos to the DNS requests of these query types, empty means all
+	QTypes []uint16
+	// TruncatePartial and TruncateTCPFail are the options of the truncate action
+	TruncatePartial bool
+	TruncateTCPFail bool
+	// MalformedVariant and MalformedSeed are the options of the malformed action
+	MalformedVariant string
+	MalformedSeed    int64
+	// MutateModes and MutateTTL are the options of the mutate action
+	MutateModes []string
+	MutateTTL   uint32
+	// DropCount, DropFraction and DropIPs are the options of the partial action
+	DropCount    int
+	DropFraction float64
+	DropIPs      []string
+	// HotspotIP is the endpoint the hotspot action pins the answers to, empty means the first one
+	HotspotIP string
+	// Misroute maps the source services to the target services of the misroute action,
+	// in the form of SERVICE.NAMESPACE
+	Misroute map[string]string
+	// TTL is the TTL of the records rewritten by the ttl action
+	TTL uint32
+	// ChaosTTL is the TTL of the records in the chaos answers, the default one is used unless
+	// ChaosTTLSet is set
+	ChaosTTL    uint32
+	ChaosTTLSet bool
+
+	// Experiment is the experiment which the pod belongs to,
+	// it is nil for the pods configured in Corefile
+	Experiment *Experiment
+}
+
+// chaosTTL returns the TTL of the records in the chaos answers
+func (p *PodInfo) chaosTTL() uint32 {
+	if !p.ChaosTTLSet {
+		return defaultChaosTTL
+	}
+	return p.ChaosTTL
 }
 
 // IsOverdue ...
@@ -50,9 +427,26 @@ func (p *PodInfo) IsOverdue() bool {
 	return false
 }
 
-func (k Kubernetes) chaosDNS(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
-	if podInfo.Action == ActionError {
+func (k *Kubernetes) chaosDNS(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	switch podInfo.Action {
+	case ActionError:
 		return dns.RcodeServerFailure, fmt.Errorf("dns chaos error")
+	case ActionCNAME, ActionCNAMELoop, ActionCNAMEChain:
+		return k.cnameChaos(ctx, w, r, state, podInfo)
+	case ActionTruncate:
+		return k.truncateChaos(ctx, w, r, state, podInfo)
+	case ActionMalformed:
+		return k.malformedChaos(ctx, w, r, state, podInfo)
+	case ActionMutate:
+		return k.mutateChaos(ctx, w, r, state, podInfo)
+	case ActionPartial:
+		return k.partialChaos(ctx, w, r, state, podInfo)
+	case ActionHotspot:
+		return k.hotspotChaos(ctx, w, r, state, podInfo)
+	case ActionMisroute:
+		return k.misrouteChaos(ctx, w, r, state, podInfo)
+	case ActionTTL:
+		return k.ttlChaos(ctx, w, r, state, podInfo)
 	}
 
 	// return random IP
@@ -63,21 +457,21 @@ func (k Kubernetes) chaosDNS(ctx context.Context, w dns.ResponseWriter, r *dns.M
 	// TODO: support more type
 	switch state.QType() {
 	case dns.TypeA:
-		ips := []net.IP{getRandomIPv4()}
+		ips := []net.IP{randomIPv4(podInfo, qname)}
 		log.Debugf("dns.TypeA %v", ips)
-		answers = a(qname, 10, ips)
+		answers = a(qname, podInfo.chaosTTL(), ips)
 	case dns.TypeAAAA:
 		// TODO: return random IP
-		ips := []net.IP{net.IP{0x20, 0x1, 0xd, 0xb8, 0, 0, 0, 0, 0, 0, 0x1, 0x23, 0, 0x12, 0, 0x1}}
+		ips := []net.IP{chaosIPv6}
 		log.Debugf("dns.TypeAAAA %v", ips)
-		answers = aaaa(qname, 10, ips)
+		answers = aaaa(qname, podInfo.chaosTTL(), ips)
 	}
 
 	if len(answers) == 0 {
 		return dns.RcodeServerFailure, nil
 	}
 
-	log.Infof("answers %v", answers)
+	log.Debugf("answers %v", answers)
 
 	m := new(dns.Msg)
 	m.SetReply(r)
@@ -89,6 +483,9 @@ func (k Kubernetes) chaosDNS(ctx context.Context, w dns.ResponseWriter, r *dns.M
 
 }
 
+// chaosIPv6 is the IP of the AAAA records in the chaos answers
+var chaosIPv6 = net.IP{0x20, 0x1, 0xd, 0xb8, 0, 0, 0, 0, 0, 0, 0x1, 0x23, 0, 0x12, 0, 0x1}
+
 func getRandomIPv4() net.IP {
 	nums := make([]byte, 0, 4)
 
@@ -99,6 +496,27 @@ func getRandomIPv4() net.IP {
 	return net.IPv4(nums[0], nums[1], nums[2], nums[3])
 }
 
+// randomIPv4 returns the IP of the random action. In RandomPerName and RandomPerPod it is derived from
+// a hash of the experiment, the name and the client pod, so that the same wrong IP is returned for the
+// whole experiment and the clients with retries or caches see a consistent answer.
+func randomIPv4(podInfo *PodInfo, qname string) net.IP {
+	if podInfo.RandomMode != RandomPerName && podInfo.RandomMode != RandomPerPod {
+		return getRandomIPv4()
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(experimentLabel(podInfo)))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.ToLower(dns.Fqdn(qname))))
+	if podInfo.RandomMode == RandomPerPod {
+		h.Write([]byte{0})
+		h.Write([]byte(podInfo.Namespace + "/" + podInfo.Name))
+	}
+	sum := h.Sum(nil)
+
+	return net.IPv4(sum[0], sum[1], sum[2], sum[3])
+}
+
 // a takes a slice of net.IPs and returns a slice of A RRs.
 func a(zone string, ttl uint32, ips []net.IP) []dns.RR {
 	answers := make([]dns.RR, len(ips))
@@ -123,7 +541,7 @@ func aaaa(zone string, ttl uint32, ips []net.IP) []dns.RR {
 	return answers
 }
 
-func (k Kubernetes) getChaosPod(ip string) (*PodInfo, error) {
+func (k *Kubernetes) getChaosPod(ip string) (*PodInfo, error) {
 	k.RLock()
 
 	podInfo := k.ipPodMap[ip]
@@ -135,39 +553,152 @@ func (k Kubernetes) getChaosPod(ip string) (*PodInfo, error) {
 	if podInfo.IsOverdue() {
 		k.RUnlock()
 
-		v1Pod, err := k.getPodFromCluster(podInfo.Namespace, podInfo.Name)
-		if err != nil {
+		if err := k.refreshPod(podInfo); err != nil {
 			return nil, err
 		}
+		return podInfo, nil
+	}
+
+	k.RUnlock()
+	return podInfo, nil
+}
+
+// refreshPod updates the IP of the pod from the cluster, the failures of the pods of experiments
+// are recorded to be reported by the grpc health service
+func (k *Kubernetes) refreshPod(podInfo *PodInfo) error {
+	v1Pod, err := k.getPodFromCluster(podInfo.Namespace, podInfo.Name)
+	if podInfo.Experiment != nil {
+		k.recordResolve(podInfo, err)
+	}
+	if err != nil {
+		podResolutionFailures.WithLabelValues(experimentLabel(podInfo), podInfo.Namespace).Inc()
+		podInfo.Experiment.addSeries(metricSeries{namespace: podInfo.Namespace})
+		log.Errorf("fail to refresh the IP of pod %s/%s: %v", podInfo.Namespace, podInfo.Name, err)
+		return err
+	}
+
+	k.Lock()
+	defer k.Unlock()
 
-		if v1Pod.Status.PodIP != podInfo.IP {
-			k.Lock()
-			podInfo.IP = v1Pod.Status.PodIP
-			podInfo.LastUpdateTime = time.Now()
+	if k.podMap[podInfo.Namespace][podInfo.Name] != podInfo {
+		// the pod is released or replaced in the meantime
+		return nil
+	}
+
+	podInfo.LastUpdateTime = time.Now()
+	if v1Pod.Status.PodIP != podInfo.IP {
+		// Pod's IP is changed, so delete the old IP
+		delete(k.ipPodMap, podInfo.IP)
+		podInfo.IP = v1Pod.Status.PodIP
+		k.ipPodMap[podInfo.IP] = podInfo
+	}
+
+	return nil
+}
+
+// recordResolve records the result of refreshing the IP of a pod of an experiment
+func (k *Kubernetes) recordResolve(podInfo *PodInfo, err error) {
+	k.resolveLock.Lock()
+	defer k.resolveLock.Unlock()
+
+	if err == nil {
+		delete(k.resolveFailures, podInfo)
+		return
+	}
+
+	failure, ok := k.resolveFailures[podInfo]
+	if !ok {
+		failure = &resolveFailure{}
+		k.resolveFailures[podInfo] = failure
+	}
+	failure.err = err
+	failure.count++
+
+	interval := podRetryInterval
+	for i := 1; i < failure.count && interval < maxPodRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxPodRetryInterval {
+		interval = maxPodRetryInterval
+	}
+	failure.nextRetry = time.Now().Add(interval)
+}
+
+// podsToRetry returns the pods whose IP failed to be refreshed and should be retried at now, the
+// retries back off so that the pods which can't be resolved don't flood the API server
+func (k *Kubernetes) podsToRetry(now time.Time) []*PodInfo {
+	failed := k.failedPods()
+
+	k.resolveLock.Lock()
+	defer k.resolveLock.Unlock()
+
+	podInfos := make([]*PodInfo, 0, len(failed))
+	for _, podInfo := range failed {
+		if failure, ok := k.resolveFailures[podInfo]; ok && !now.Before(failure.nextRetry) {
+			podInfos = append(podInfos, podInfo)
+		}
+	}
+	return podInfos
+}
 
-			delete(k.ipPodMap, podInfo.IP)
-			k.ipPodMap[v1Pod.Status.PodIP] = podInfo
-			k.Unlock()
+// failedPods returns the pods of experiments whose IP fails to be refreshed, the pods released
+// or replaced by experiments are forgotten
+func (k *Kubernetes) failedPods() []*PodInfo {
+	k.RLock()
+	defer k.RUnlock()
+	k.resolveLock.Lock()
+	defer k.resolveLock.Unlock()
+
+	podInfos := make([]*PodInfo, 0, len(k.resolveFailures))
+	for podInfo := range k.resolveFailures {
+		if k.podMap[podInfo.Namespace][podInfo.Name] != podInfo {
+			delete(k.resolveFailures, podInfo)
+			continue
 		}
+		podInfos = append(podInfos, podInfo)
+	}
 
-		return podInfo, nil
+	return podInfos
+}
+
+// experimentPods returns the pods which chaos of the experiment is applied to,
+// the caller should hold the lock
+func (k *Kubernetes) experimentPods(experiment *Experiment) []*PodInfo {
+	podInfos := make([]*PodInfo, 0, len(experiment.Request.Pods))
+	for _, pod := range experiment.Request.Pods {
+		if podInfo, ok := k.podMap[pod.Namespace][pod.Name]; ok && podInfo.Experiment == experiment {
+			podInfos = append(podInfos, podInfo)
+		}
 	}
 
-	k.RUnlock()
-	return podInfo, nil
+	return podInfos
 }
 
 // needChaos judges weather should do chaos for the request
-func (k Kubernetes) needChaos(podInfo *PodInfo, records []dns.RR, name string) bool {
+func (k *Kubernetes) needChaos(podInfo *PodInfo, records []dns.RR, state request.Request) bool {
 	if podInfo == nil {
 		return false
 	}
 
-	if podInfo.Scope == ScopeAll {
+	if podInfo.Protocol != "" && podInfo.Protocol != state.Proto() {
+		return false
+	}
+
+	if len(podInfo.QTypes) != 0 && !hasQType(podInfo.QTypes, state.QType()) {
+		return false
+	}
+
+	switch inner := plugin.Zones(k.Zones).Matches(state.Name()) != ""; {
+	case podInfo.Scope == ScopeInner && !inner, podInfo.Scope == ScopeOuter && inner:
+		return false
+	}
+
+	// the pods without patterns, including the ones configured in Corefile, match all the names
+	if podInfo.Selector == nil {
 		return true
 	}
 
-	rules := podInfo.Selector.Match(name, "")
+	rules := podInfo.Selector.Match(state.QName(), "")
 	if len(rules) == 0 {
 		return false
 	}
@@ -180,7 +711,7 @@ func (k Kubernetes) needChaos(podInfo *PodInfo, records []dns.RR, name string) b
 	return match
 }
 
-func (k Kubernetes) getPodFromCluster(namespace, name string) (*api.Pod, error) {
+func (k *Kubernetes) getPodFromCluster(namespace, name string) (*api.Pod, error) {
 	pods := k.Client.Pods(namespace)
 	if pods == nil {
 		log.Infof("getPodFromCluster, pods is nil")
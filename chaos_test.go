@@ -0,0 +1,307 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+	"github.com/coredns/coredns/request"
+
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chaosClientIP is the IP of the client in test.ResponseWriter
+const chaosClientIP = "10.240.0.1"
+
+// newChaosTestKubernetes returns a Kubernetes which experiment named "chaos" targets the client of test.ResponseWriter
+func newChaosTestKubernetes(t *testing.T, req *pb.SetDNSChaosRequest) *Kubernetes {
+	k := newGRPCTestKubernetes(testPod("testns", "client", chaosClientIP))
+	k.APIConn = &APIConnServeTest{}
+	k.Next = test.NextHandler(dns.RcodeSuccess, nil)
+	k.Namespaces = map[string]struct{}{"testns": {}}
+
+	req.Name = "chaos"
+	req.Pods = []*pb.Pod{{Namespace: "testns", Name: "client"}}
+	if _, err := k.SetDNSChaos(context.Background(), req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	return k
+}
+
+func TestChaosDNS(t *testing.T) {
+	tests := []struct {
+		req           *pb.SetDNSChaosRequest
+		qname         string
+		qtype         uint16
+		expectedRcode int
+		expectChaos   bool
+	}{
+		{&pb.SetDNSChaosRequest{Action: ActionError}, "svc1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeServerFailure, true},
+		{&pb.SetDNSChaosRequest{Action: ActionRandom}, "svc1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess, true},
+		{&pb.SetDNSChaosRequest{Action: ActionRandom}, "svc1.testns.svc.cluster.local.", dns.TypeAAAA, dns.RcodeSuccess, true},
+		// the name doesn't match the pattern
+		{&pb.SetDNSChaosRequest{Action: ActionError, Patterns: []string{"google.com"}}, "svc1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess, false},
+		{&pb.SetDNSChaosRequest{Action: ActionError, Patterns: []string{"svc1.testns.*"}}, "svc1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeServerFailure, true},
+		// the inner names are in the zones of the plugin
+		{&pb.SetDNSChaosRequest{Action: ActionError, Scope: ScopeInner}, "svc1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeServerFailure, true},
+		{&pb.SetDNSChaosRequest{Action: ActionError, Scope: ScopeInner}, "google.com.", dns.TypeA, dns.RcodeSuccess, false},
+		{&pb.SetDNSChaosRequest{Action: ActionError, Scope: ScopeOuter}, "svc1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess, false},
+		{&pb.SetDNSChaosRequest{Action: ActionError, Scope: ScopeOuter, Patterns: []string{"google.com"}}, "google.com.", dns.TypeA, dns.RcodeServerFailure, true},
+	}
+
+	ctx := context.TODO()
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, tc.req)
+
+		m := new(dns.Msg)
+		m.SetQuestion(tc.qname, tc.qtype)
+		w := dnstest.NewRecorder(&test.ResponseWriter{})
+
+		rcode, _ := k.ServeDNS(ctx, w, m)
+		if w.Msg != nil {
+			rcode = w.Msg.Rcode
+		}
+		if rcode != tc.expectedRcode {
+			t.Errorf("Test %d: Expected rcode %d, got %d", i, tc.expectedRcode, rcode)
+		}
+
+		if hits := k.chaosMap["chaos"].HitCount(); (hits == 1) != tc.expectChaos {
+			t.Errorf("Test %d: Expected chaos %v, got %d hits", i, tc.expectChaos, hits)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := context.TODO()
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionError, DryRun: true})
+	experiment := k.chaosMap["chaos"]
+
+	serve := func() int {
+		m := new(dns.Msg)
+		m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+		w := dnstest.NewRecorder(&test.ResponseWriter{})
+		rcode, _ := k.ServeDNS(ctx, w, m)
+		if w.Msg != nil {
+			rcode = w.Msg.Rcode
+		}
+		return rcode
+	}
+
+	// the real answer is served in dry run
+	if rcode := serve(); rcode != dns.RcodeSuccess {
+		t.Errorf("Expected rcode %d in dry run, got %d", dns.RcodeSuccess, rcode)
+	}
+	if experiment.HitCount() != 0 || experiment.DryRunHitCount() != 1 {
+		t.Errorf("Expected 0 hits and 1 dry run hit, got %d and %d", experiment.HitCount(), experiment.DryRunHitCount())
+	}
+
+	// promote the experiment to a live one
+	req := &pb.SetDNSChaosRequest{Name: "chaos", Action: ActionError, Pods: experiment.Request.Pods}
+	if _, err := k.UpdateDNSChaos(ctx, &pb.UpdateDNSChaosRequest{Chaos: req}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if k.chaosMap["chaos"] != experiment {
+		t.Fatalf("Expected the experiment to be kept")
+	}
+	if rcode := serve(); rcode != dns.RcodeServerFailure {
+		t.Errorf("Expected rcode %d after promoted, got %d", dns.RcodeServerFailure, rcode)
+	}
+	if experiment.HitCount() != 1 || experiment.DryRunHitCount() != 1 {
+		t.Errorf("Expected 1 hit and 1 dry run hit, got %d and %d", experiment.HitCount(), experiment.DryRunHitCount())
+	}
+}
+
+func TestDNSChaosStats(t *testing.T) {
+	ctx := context.TODO()
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionError, Patterns: []string{"svc1.testns.*"}})
+	experiment := k.chaosMap["chaos"]
+
+	serve := func(qname string) {
+		m := new(dns.Msg)
+		m.SetQuestion(qname, dns.TypeA)
+		k.ServeDNS(ctx, dnstest.NewRecorder(&test.ResponseWriter{}), m)
+	}
+
+	stats, err := k.GetDNSChaosStats(ctx, &pb.GetDNSChaosStatsRequest{Name: "chaos"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Matched != 0 || stats.Injected != 0 || stats.FirstInjectionTime != nil {
+		t.Errorf("Expected empty stats, got %v", stats)
+	}
+
+	serve("svc1.testns.svc.cluster.local.")
+	serve("svc1.testns.svc.cluster.local.")
+	// not matched
+	serve("svc2.testns.svc.cluster.local.")
+
+	req := &pb.SetDNSChaosRequest{Name: "chaos", Action: ActionRandom, Patterns: experiment.Request.Patterns, Pods: experiment.Request.Pods}
+	if _, err := k.UpdateDNSChaos(ctx, &pb.UpdateDNSChaosRequest{Chaos: req}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	serve("svc1.testns.svc.cluster.local.")
+
+	if _, err := k.PauseAll(ctx, &pb.PauseAllRequest{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// matched but not injected
+	serve("svc1.testns.svc.cluster.local.")
+
+	resp, err := k.CancelDNSChaos(ctx, &pb.CancelDNSChaosRequest{Name: "chaos"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	stats = resp.Stats
+	if stats == nil {
+		t.Fatalf("Expected the final stats in the response of CancelDNSChaos")
+	}
+	if stats.Name != "chaos" || stats.Matched != 4 || stats.Injected != 3 {
+		t.Errorf("Expected 4 matched and 3 injected, got %d and %d", stats.Matched, stats.Injected)
+	}
+	if stats.InjectedByAction[ActionError] != 2 || stats.InjectedByAction[ActionRandom] != 1 {
+		t.Errorf("Expected 2 errors and 1 random injected, got %v", stats.InjectedByAction)
+	}
+	if len(stats.PodHits) != 1 || stats.PodHits[0].Namespace != "testns" || stats.PodHits[0].Name != "client" || stats.PodHits[0].Hits != 3 {
+		t.Errorf("Expected 3 hits of pod testns/client, got %v", stats.PodHits)
+	}
+	if stats.FirstInjectionTime == nil || stats.LastInjectionTime == nil {
+		t.Errorf("Expected injection times to be set, got %v and %v", stats.FirstInjectionTime, stats.LastInjectionTime)
+	}
+
+	if _, err := k.GetDNSChaosStats(ctx, &pb.GetDNSChaosStatsRequest{Name: "chaos"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound after canceled, got %v", err)
+	}
+}
+
+func TestRandomMode(t *testing.T) {
+	experiment := &Experiment{Name: "chaos"}
+	pod := func(name, mode string) *PodInfo {
+		return &PodInfo{Namespace: "testns", Name: name, Action: ActionRandom, RandomMode: mode, Experiment: experiment}
+	}
+
+	tests := []struct {
+		podInfo       *PodInfo
+		qname         string
+		other         *PodInfo
+		otherQname    string
+		expectedEqual bool
+	}{
+		{pod("client-0", RandomPerName), "google.com.", pod("client-0", RandomPerName), "google.com.", true},
+		// the name is case insensitive
+		{pod("client-0", RandomPerName), "google.com.", pod("client-0", RandomPerName), "Google.COM", true},
+		{pod("client-0", RandomPerName), "google.com.", pod("client-1", RandomPerName), "google.com.", true},
+		{pod("client-0", RandomPerName), "google.com.", pod("client-0", RandomPerName), "example.com.", false},
+		{pod("client-0", RandomPerName), "google.com.", &PodInfo{Namespace: "testns", Name: "client-0", RandomMode: RandomPerName, Experiment: &Experiment{Name: "other"}}, "google.com.", false},
+		{pod("client-0", RandomPerPod), "google.com.", pod("client-0", RandomPerPod), "google.com.", true},
+		{pod("client-0", RandomPerPod), "google.com.", pod("client-1", RandomPerPod), "google.com.", false},
+	}
+
+	for i, tc := range tests {
+		ip, other := randomIPv4(tc.podInfo, tc.qname), randomIPv4(tc.other, tc.otherQname)
+		if ip.Equal(other) != tc.expectedEqual {
+			t.Errorf("Test %d: Expected equal %v, got %s and %s", i, tc.expectedEqual, ip, other)
+		}
+	}
+
+	// the answers of the DNS requests are stable
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionRandom, RandomMode: RandomPerName})
+	answers := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		m := new(dns.Msg)
+		m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+		w := dnstest.NewRecorder(&test.ResponseWriter{})
+		k.ServeDNS(context.TODO(), w, m)
+		if w.Msg == nil || len(w.Msg.Answer) != 1 {
+			t.Fatalf("Expected an answer, got %v", w.Msg)
+		}
+		answers = append(answers, w.Msg.Answer[0].(*dns.A).A.String())
+	}
+	if answers[0] != answers[1] {
+		t.Errorf("Expected the same answer, got %v", answers)
+	}
+}
+
+func TestCorefileChaosScope(t *testing.T) {
+	k := New([]string{"cluster.local."})
+	tests := []struct {
+		scope       string
+		qname       string
+		expectChaos bool
+	}{
+		{ScopeAll, "google.com.", true},
+		{ScopeInner, "svc1.testns.svc.cluster.local.", true},
+		{ScopeInner, "google.com.", false},
+		{ScopeOuter, "svc1.testns.svc.cluster.local.", false},
+		{ScopeOuter, "google.com.", true},
+	}
+
+	for i, tc := range tests {
+		// the pods configured in Corefile have no selector
+		podInfo := &PodInfo{Namespace: "testns", Name: "client", Action: ActionError, Scope: tc.scope}
+		m := new(dns.Msg)
+		m.SetQuestion(tc.qname, dns.TypeA)
+		state := request.Request{W: &test.ResponseWriter{}, Req: m}
+
+		if needChaos := k.needChaos(podInfo, nil, state); needChaos != tc.expectChaos {
+			t.Errorf("Test %d: Expected chaos %v, got %v", i, tc.expectChaos, needChaos)
+		}
+	}
+}
+
+func TestProtocolChaos(t *testing.T) {
+	tests := []struct {
+		protocol    string
+		tcp         bool
+		expectChaos bool
+	}{
+		{"", false, true},
+		{"", true, true},
+		{ProtocolUDP, false, true},
+		{ProtocolUDP, true, false},
+		{ProtocolTCP, false, false},
+		{ProtocolTCP, true, true},
+	}
+
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionError, Protocol: tc.protocol})
+
+		m := new(dns.Msg)
+		m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+		k.ServeDNS(context.TODO(), dnstest.NewRecorder(&test.ResponseWriter{TCP: tc.tcp}), m)
+
+		if hits := k.chaosMap["chaos"].HitCount(); (hits == 1) != tc.expectChaos {
+			t.Errorf("Test %d: Expected chaos %v, got %d hits", i, tc.expectChaos, hits)
+		}
+	}
+}
+
+func TestQTypeChaos(t *testing.T) {
+	tests := []struct {
+		qtypes      []string
+		qtype       uint16
+		expectChaos bool
+	}{
+		{nil, dns.TypeA, true},
+		{[]string{"AAAA"}, dns.TypeAAAA, true},
+		// A records are left alone to test the fallback to IPv4
+		{[]string{"AAAA"}, dns.TypeA, false},
+		{[]string{"srv", "AAAA"}, dns.TypeSRV, true},
+	}
+
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionError, Qtypes: tc.qtypes})
+
+		m := new(dns.Msg)
+		m.SetQuestion("svc1.testns.svc.cluster.local.", tc.qtype)
+		k.ServeDNS(context.TODO(), dnstest.NewRecorder(&test.ResponseWriter{}), m)
+
+		if hits := k.chaosMap["chaos"].HitCount(); (hits == 1) != tc.expectChaos {
+			t.Errorf("Test %d: Expected chaos %v, got %d hits", i, tc.expectChaos, hits)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coredns/coredns/plugin/pkg/dnsutil"
+	"github.com/coredns/coredns/request"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// the names made up by the CNAME actions are prefixed to the queried name
+	cnameLoopPrefix  = "chaos-loop"
+	cnameChainPrefix = "chaos-chain"
+
+	// defaultCNAMEDepth is the count of CNAMEs of the cname_chain action if it is not set
+	defaultCNAMEDepth = 8
+	// maxCNAMEDepth limits the count of CNAMEs of the cname_chain action, so that the answer fits in a DNS message
+	maxCNAMEDepth = 64
+)
+
+// cnameChaos answers with CNAMEs instead of the real records. ActionCNAME answers name -> target
+// with the records of the target resolved through Upstream, ActionCNAMELoop answers
+// name -> chaos-loop.name -> name, and ActionCNAMEChain answers name -> chaos-chain-1.name -> ... -> target,
+// the target is chaos-chain-DEPTH.name if it is not set, which doesn't resolve to anything.
+func (k *Kubernetes) cnameChaos(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	qname := state.QName()
+	target := podInfo.CNAMETarget
+	ttl := podInfo.chaosTTL()
+
+	var answers []dns.RR
+	switch podInfo.Action {
+	case ActionCNAME:
+		answers = append(answers, cname(qname, target, ttl))
+	case ActionCNAMELoop:
+		loop := dnsutil.Join(cnameLoopPrefix, qname)
+		answers = append(answers, cname(qname, loop, ttl), cname(loop, qname, ttl))
+	case ActionCNAMEChain:
+		depth := podInfo.CNAMEDepth
+		if depth == 0 {
+			depth = defaultCNAMEDepth
+		}
+		if target == "" {
+			target = dnsutil.Join(fmt.Sprintf("%s-%d", cnameChainPrefix, depth), qname)
+		}
+
+		name := qname
+		for i := 1; i < depth; i++ {
+			next := dnsutil.Join(fmt.Sprintf("%s-%d", cnameChainPrefix, i), qname)
+			answers = append(answers, cname(name, next, ttl))
+			name = next
+		}
+		answers = append(answers, cname(name, target, ttl))
+	}
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+	m.Answer = answers
+
+	// the real target is resolved, so that the client gets the full chain
+	if podInfo.CNAMETarget != "" && state.QType() != dns.TypeCNAME {
+		resp, err := k.Lookup(withChaosLookup(ctx), state, target, state.QType())
+		if err != nil {
+			log.Warningf("fail to resolve CNAME target %s: %v", target, err)
+		} else if resp != nil {
+			m.Rcode = resp.Rcode
+			m.Answer = append(m.Answer, resp.Answer...)
+		}
+	}
+
+	w.WriteMsg(m)
+	return dns.RcodeSuccess, nil
+}
+
+// cname returns a CNAME RR from name to target
+func cname(name, target string, ttl uint32) dns.RR {
+	return &dns.CNAME{
+		Hdr:    dns.RR_Header{Name: name, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: ttl},
+		Target: target,
+	}
+}
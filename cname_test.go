@@ -0,0 +1,116 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/core/dnsserver"
+	"github.com/coredns/coredns/plugin"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+)
+
+// newChaosLookupContext returns a context of a server whose only plugin is k, so that the lookups
+// made while injecting chaos are served by k
+func newChaosLookupContext(t *testing.T, k *Kubernetes) context.Context {
+	server, err := dnsserver.NewServer("dns://:53", []*dnsserver.Config{{
+		Zone:   ".",
+		Plugin: []plugin.Plugin{func(plugin.Handler) plugin.Handler { return k }},
+	}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return context.WithValue(context.TODO(), dnsserver.Key{}, server)
+}
+
+func TestCNAMEChaos(t *testing.T) {
+	tests := []struct {
+		req             *pb.SetDNSChaosRequest
+		qtype           uint16
+		expectedAnswers []string
+	}{
+		{
+			&pb.SetDNSChaosRequest{Action: ActionCNAME, CnameTarget: "svc1.testns.svc.cluster.local"},
+			dns.TypeA,
+			[]string{
+				"google.com.	10	IN	CNAME	svc1.testns.svc.cluster.local.",
+				"svc1.testns.svc.cluster.local.	5	IN	A	10.0.0.1",
+			},
+		},
+		// the target isn't resolved for a CNAME query
+		{
+			&pb.SetDNSChaosRequest{Action: ActionCNAME, CnameTarget: "svc1.testns.svc.cluster.local"},
+			dns.TypeCNAME,
+			[]string{"google.com.	10	IN	CNAME	svc1.testns.svc.cluster.local."},
+		},
+		{
+			&pb.SetDNSChaosRequest{Action: ActionCNAMELoop},
+			dns.TypeA,
+			[]string{
+				"google.com.	10	IN	CNAME	chaos-loop.google.com.",
+				"chaos-loop.google.com.	10	IN	CNAME	google.com.",
+			},
+		},
+		{
+			&pb.SetDNSChaosRequest{Action: ActionCNAMEChain, CnameDepth: 3, CnameTarget: "svc1.testns.svc.cluster.local"},
+			dns.TypeA,
+			[]string{
+				"google.com.	10	IN	CNAME	chaos-chain-1.google.com.",
+				"chaos-chain-1.google.com.	10	IN	CNAME	chaos-chain-2.google.com.",
+				"chaos-chain-2.google.com.	10	IN	CNAME	svc1.testns.svc.cluster.local.",
+				"svc1.testns.svc.cluster.local.	5	IN	A	10.0.0.1",
+			},
+		},
+		// the chain doesn't resolve to anything without a target
+		{
+			&pb.SetDNSChaosRequest{Action: ActionCNAMEChain, CnameDepth: 2},
+			dns.TypeA,
+			[]string{
+				"google.com.	10	IN	CNAME	chaos-chain-1.google.com.",
+				"chaos-chain-1.google.com.	10	IN	CNAME	chaos-chain-2.google.com.",
+			},
+		},
+	}
+
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, tc.req)
+		ctx := newChaosLookupContext(t, k)
+
+		m := new(dns.Msg)
+		m.SetQuestion("google.com.", tc.qtype)
+		w := dnstest.NewRecorder(&test.ResponseWriter{})
+		k.ServeDNS(ctx, w, m)
+
+		if w.Msg == nil {
+			t.Fatalf("Test %d: Expected an answer, got none", i)
+		}
+		if len(w.Msg.Answer) != len(tc.expectedAnswers) {
+			t.Fatalf("Test %d: Expected %d answers, got %v", i, len(tc.expectedAnswers), w.Msg.Answer)
+		}
+		for j, expected := range tc.expectedAnswers {
+			if rr, _ := dns.NewRR(expected); !dns.IsDuplicate(w.Msg.Answer[j], rr) {
+				t.Errorf("Test %d: Expected answer %s, got %s", i, expected, w.Msg.Answer[j])
+			}
+		}
+	}
+}
+
+func TestCNAMEChainDepth(t *testing.T) {
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionCNAMEChain, CnameDepth: maxCNAMEDepth})
+
+	m := new(dns.Msg)
+	m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+	w := dnstest.NewRecorder(&test.ResponseWriter{})
+	k.ServeDNS(context.TODO(), w, m)
+
+	if w.Msg == nil || len(w.Msg.Answer) != maxCNAMEDepth {
+		t.Fatalf("Expected %d answers, got %v", maxCNAMEDepth, w.Msg)
+	}
+	// the longest chain still fits in a DNS message over TCP
+	if _, err := w.Msg.Pack(); err != nil {
+		t.Errorf("Expected the answer to be packed, got %v", err)
+	}
+}
@@ -0,0 +1,441 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// crdExperimentPrefix is the prefix of the experiments created from DNSChaos objects,
+	// it keeps them apart from the experiments set through the gRPC service
+	crdExperimentPrefix = "crd/"
+
+	// crdStatusInterval is the interval of writing status back to the DNSChaos objects
+	crdStatusInterval = 10 * time.Second
+
+	// crdResyncInterval is the interval of retrying the DNSChaos objects which fail to be applied,
+	// e.g. for their pods aren't created yet
+	crdResyncInterval = 30 * time.Second
+)
+
+// dnsChaosGVR is the resource of the DNSChaos custom resource, see e2e/manifests/chaos-coredns-crd.yaml
+var dnsChaosGVR = schema.GroupVersionResource{
+	Group:    "k8sdnschaos.chaos-mesh.org",
+	Version:  "v1alpha1",
+	Resource: "dnschaos",
+}
+
+// crdWatcher applies and removes experiments as DNSChaos objects are created, updated and deleted,
+// and writes the status of the experiments back to the objects.
+type crdWatcher struct {
+	k         *Kubernetes
+	client    dynamic.Interface
+	namespace string
+	informer  cache.SharedIndexInformer
+
+	// errLock protects errs, which saves the last error of applying each DNSChaos object
+	errLock sync.Mutex
+	errs    map[string]string
+
+	stopLock sync.Mutex
+	shutdown bool
+	stopCh   chan struct{}
+}
+
+func newCRDWatcher(k *Kubernetes, client dynamic.Interface, namespace string) *crdWatcher {
+	w := &crdWatcher{
+		k:         k,
+		client:    client,
+		namespace: namespace,
+		errs:      make(map[string]string),
+		stopCh:    make(chan struct{}),
+	}
+
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, crdResyncInterval, namespace, nil)
+	w.informer = factory.ForResource(dnsChaosGVR).Informer()
+	w.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{AddFunc: w.Add, UpdateFunc: w.Update, DeleteFunc: w.Delete})
+
+	return w
+}
+
+// Run starts the watcher.
+func (w *crdWatcher) Run() {
+	go w.informer.Run(w.stopCh)
+	go func() {
+		if cache.WaitForCacheSync(w.stopCh, w.informer.HasSynced) {
+			w.reconcile()
+		}
+	}()
+	go wait.Until(w.syncStatus, crdStatusInterval, w.stopCh)
+	<-w.stopCh
+}
+
+// Stop stops the watcher, the experiments already applied are kept.
+func (w *crdWatcher) Stop() error {
+	w.stopLock.Lock()
+	defer w.stopLock.Unlock()
+
+	if !w.shutdown {
+		close(w.stopCh)
+		w.shutdown = true
+
+		return nil
+	}
+
+	return fmt.Errorf("shutdown already in progress")
+}
+
+// Add applies the experiment of a new DNSChaos object.
+func (w *crdWatcher) Add(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	w.apply(u)
+}
+
+// Update updates the experiment in place if the spec of the DNSChaos object is changed,
+// the objects which failed to be applied are retried on every resync.
+func (w *crdWatcher) Update(oldObj, newObj interface{}) {
+	oldU, ok := oldObj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	newU, ok := newObj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	// the status written back by syncStatus doesn't change the generation
+	if oldU.GetGeneration() == newU.GetGeneration() && !w.failed(experimentNameOf(newU)) {
+		return
+	}
+
+	w.apply(newU)
+}
+
+// Delete removes the experiment of a deleted DNSChaos object.
+func (w *crdWatcher) Delete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	name := experimentNameOf(u)
+	w.cancel(name)
+
+	w.errLock.Lock()
+	delete(w.errs, name)
+	w.errLock.Unlock()
+}
+
+// reconcile cancels the experiments of the DNSChaos objects which were deleted while the watcher
+// wasn't running, e.g. while Corefile was being reloaded.
+func (w *crdWatcher) reconcile() {
+	var names []string
+	w.k.RLock()
+	for name := range w.k.chaosMap {
+		if strings.HasPrefix(name, crdExperimentPrefix) {
+			names = append(names, name)
+		}
+	}
+	w.k.RUnlock()
+
+	for _, name := range names {
+		key := strings.TrimPrefix(name, crdExperimentPrefix)
+		if namespace, _, err := cache.SplitMetaNamespaceKey(key); err != nil || !w.watches(namespace) {
+			continue
+		}
+		if _, exists, _ := w.informer.GetStore().GetByKey(key); !exists {
+			log.Infof("DNSChaos %s is deleted, cancel experiment %s", key, name)
+			w.cancel(name)
+		}
+	}
+}
+
+// watches returns whether the objects in the namespace are watched.
+func (w *crdWatcher) watches(namespace string) bool {
+	return w.namespace == "" || w.namespace == namespace
+}
+
+// failed returns whether the experiment failed to be applied last time.
+func (w *crdWatcher) failed(name string) bool {
+	w.errLock.Lock()
+	defer w.errLock.Unlock()
+
+	_, ok := w.errs[name]
+	return ok
+}
+
+func (w *crdWatcher) apply(u *unstructured.Unstructured) {
+	name := experimentNameOf(u)
+
+	ctx := withAuditCaller(context.Background(), fmt.Sprintf("DNSChaos %s/%s", u.GetNamespace(), u.GetName()))
+	req, err := dnsChaosRequest(u)
+	if err == nil {
+		// NotFound is also returned for the missing pods, so check the experiment itself
+		if _, getErr := w.k.GetDNSChaos(ctx, &pb.GetDNSChaosRequest{Name: name}); status.Code(getErr) == codes.NotFound {
+			_, err = w.k.SetDNSChaos(ctx, req)
+		} else {
+			_, err = w.k.UpdateDNSChaos(ctx, &pb.UpdateDNSChaosRequest{Chaos: req})
+		}
+	}
+
+	w.errLock.Lock()
+	defer w.errLock.Unlock()
+
+	if err != nil {
+		log.Errorf("fail to apply DNSChaos %s/%s: %v", u.GetNamespace(), u.GetName(), err)
+		w.errs[name] = err.Error()
+		return
+	}
+	delete(w.errs, name)
+}
+
+func (w *crdWatcher) cancel(name string) {
+	caller := "DNSChaos " + strings.TrimPrefix(name, crdExperimentPrefix)
+	_, err := w.k.CancelDNSChaos(withAuditCaller(context.Background(), caller), &pb.CancelDNSChaosRequest{Name: name})
+	if err != nil {
+		log.Errorf("fail to cancel experiment %s: %v", name, err)
+	}
+}
+
+// syncStatus writes the status of the experiments back to the DNSChaos objects which status is changed.
+func (w *crdWatcher) syncStatus() {
+	for _, obj := range w.informer.GetStore().List() {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+
+		newStatus := w.status(experimentNameOf(u))
+		oldStatus, _, _ := unstructured.NestedMap(u.Object, "status")
+		if reflect.DeepEqual(newStatus, oldStatus) {
+			continue
+		}
+
+		u = u.DeepCopy()
+		if err := unstructured.SetNestedMap(u.Object, newStatus, "status"); err != nil {
+			log.Errorf("fail to set status of DNSChaos %s/%s: %v", u.GetNamespace(), u.GetName(), err)
+			continue
+		}
+
+		_, err := w.client.Resource(dnsChaosGVR).Namespace(u.GetNamespace()).UpdateStatus(context.Background(), u, meta.UpdateOptions{})
+		if err != nil {
+			log.Warningf("fail to update status of DNSChaos %s/%s: %v", u.GetNamespace(), u.GetName(), err)
+		}
+	}
+}
+
+// status returns the status of an experiment in the form of the DNSChaos status.
+func (w *crdWatcher) status(name string) map[string]interface{} {
+	experimentStatus := map[string]interface{}{
+		"active":      false,
+		"matchedPods": []interface{}{},
+		"hits":        int64(0),
+		"dryRunHits":  int64(0),
+	}
+
+	w.errLock.Lock()
+	if msg, ok := w.errs[name]; ok {
+		experimentStatus["message"] = msg
+	}
+	w.errLock.Unlock()
+
+	w.k.RLock()
+	defer w.k.RUnlock()
+
+	experiment, ok := w.k.chaosMap[name]
+	if !ok {
+		return experimentStatus
+	}
+
+	pods := make([]string, 0, len(experiment.Request.Pods))
+	for _, podInfo := range w.k.experimentPods(experiment) {
+		pods = append(pods, fmt.Sprintf("%s/%s", podInfo.Namespace, podInfo.Name))
+	}
+	sort.Strings(pods)
+
+	matchedPods := make([]interface{}, 0, len(pods))
+	for _, pod := range pods {
+		matchedPods = append(matchedPods, pod)
+	}
+
+	experimentStatus["active"] = true
+	experimentStatus["matchedPods"] = matchedPods
+	experimentStatus["hits"] = experiment.HitCount()
+	experimentStatus["dryRunHits"] = experiment.DryRunHitCount()
+
+	return experimentStatus
+}
+
+// experimentNameOf returns the name of the experiment created from a DNSChaos object.
+func experimentNameOf(u *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s%s/%s", crdExperimentPrefix, u.GetNamespace(), u.GetName())
+}
+
+// dnsChaosRequest converts the spec of a DNSChaos object into a SetDNSChaosRequest,
+// the pods without namespace are treated as in the namespace of the object.
+func dnsChaosRequest(u *unstructured.Unstructured) (*pb.SetDNSChaosRequest, error) {
+	req := &pb.SetDNSChaosRequest{
+		Name: experimentNameOf(u),
+	}
+
+	var err error
+	if req.Action, _, err = unstructured.NestedString(u.Object, "spec", "action"); err != nil {
+		return nil, err
+	}
+	if req.Scope, _, err = unstructured.NestedString(u.Object, "spec", "scope"); err != nil {
+		return nil, err
+	}
+	if req.Patterns, _, err = unstructured.NestedStringSlice(u.Object, "spec", "patterns"); err != nil {
+		return nil, err
+	}
+	if req.Protocol, _, err = unstructured.NestedString(u.Object, "spec", "protocol"); err != nil {
+		return nil, err
+	}
+	if req.Qtypes, _, err = unstructured.NestedStringSlice(u.Object, "spec", "qtypes"); err != nil {
+		return nil, err
+	}
+	if req.DryRun, _, err = unstructured.NestedBool(u.Object, "spec", "dryRun"); err != nil {
+		return nil, err
+	}
+	if req.RandomMode, _, err = unstructured.NestedString(u.Object, "spec", "randomMode"); err != nil {
+		return nil, err
+	}
+	if req.CnameTarget, _, err = unstructured.NestedString(u.Object, "spec", "cnameTarget"); err != nil {
+		return nil, err
+	}
+	if req.CnameDepth, err = nestedInt32(u, "cnameDepth"); err != nil {
+		return nil, err
+	}
+	if req.MalformedVariant, _, err = unstructured.NestedString(u.Object, "spec", "malformedVariant"); err != nil {
+		return nil, err
+	}
+	if req.MalformedSeed, _, err = unstructured.NestedInt64(u.Object, "spec", "malformedSeed"); err != nil {
+		return nil, err
+	}
+	if req.MutateModes, _, err = unstructured.NestedStringSlice(u.Object, "spec", "mutateModes"); err != nil {
+		return nil, err
+	}
+	if req.MutateTtl, err = nestedUint32(u, "mutateTTL"); err != nil {
+		return nil, err
+	}
+	if req.DropCount, err = nestedInt32(u, "dropCount"); err != nil {
+		return nil, err
+	}
+	if req.DropFraction, err = nestedFloat64(u, "dropFraction"); err != nil {
+		return nil, err
+	}
+	if req.DropIps, _, err = unstructured.NestedStringSlice(u.Object, "spec", "dropIPs"); err != nil {
+		return nil, err
+	}
+	if req.HotspotIp, _, err = unstructured.NestedString(u.Object, "spec", "hotspotIP"); err != nil {
+		return nil, err
+	}
+	if req.Misroute, _, err = unstructured.NestedStringMap(u.Object, "spec", "misroute"); err != nil {
+		return nil, err
+	}
+	if req.Ttl, err = nestedUint32(u, "ttl"); err != nil {
+		return nil, err
+	}
+	// chaosTTL 0 is told apart from an unset one by its presence
+	if _, ok, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "chaosTTL"); ok {
+		chaosTTL, err := nestedUint32(u, "chaosTTL")
+		if err != nil {
+			return nil, err
+		}
+		req.ChaosTtl = wrapperspb.UInt32(chaosTTL)
+	}
+	if req.TruncatePartial, _, err = unstructured.NestedBool(u.Object, "spec", "truncatePartial"); err != nil {
+		return nil, err
+	}
+	if req.TruncateTcpFail, _, err = unstructured.NestedBool(u.Object, "spec", "truncateTCPFail"); err != nil {
+		return nil, err
+	}
+
+	pods, _, err := unstructured.NestedSlice(u.Object, "spec", "pods")
+	if err != nil {
+		return nil, err
+	}
+	for i, pod := range pods {
+		fields, ok := pod.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("spec.pods[%d] is not an object", i)
+		}
+
+		name, _, _ := unstructured.NestedString(fields, "name")
+		if name == "" {
+			return nil, fmt.Errorf("spec.pods[%d].name is required", i)
+		}
+		namespace, _, _ := unstructured.NestedString(fields, "namespace")
+		if namespace == "" {
+			namespace = u.GetNamespace()
+		}
+
+		req.Pods = append(req.Pods, &pb.Pod{Namespace: namespace, Name: name})
+	}
+
+	return req, nil
+}
+
+// nestedInt32 returns the integer field of the spec, which must fit in an int32 and not be negative.
+func nestedInt32(u *unstructured.Unstructured, field string) (int32, error) {
+	value, err := nestedInt(u, field, math.MaxInt32)
+	return int32(value), err
+}
+
+// nestedUint32 returns the integer field of the spec, which must fit in an uint32.
+func nestedUint32(u *unstructured.Unstructured, field string) (uint32, error) {
+	value, err := nestedInt(u, field, math.MaxUint32)
+	return uint32(value), err
+}
+
+func nestedInt(u *unstructured.Unstructured, field string, max int64) (int64, error) {
+	value, _, err := unstructured.NestedInt64(u.Object, "spec", field)
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 || value > max {
+		return 0, fmt.Errorf("spec.%s must be between 0 and %d, got %d", field, max, value)
+	}
+	return value, nil
+}
+
+// nestedFloat64 returns the number field of the spec, an integral number is decoded as an int64.
+func nestedFloat64(u *unstructured.Unstructured, field string) (float64, error) {
+	value, ok, err := unstructured.NestedFieldNoCopy(u.Object, "spec", field)
+	if err != nil || !ok {
+		return 0, err
+	}
+	switch value := value.(type) {
+	case float64:
+		return value, nil
+	case int64:
+		return float64(value), nil
+	default:
+		return 0, fmt.Errorf("spec.%s must be a number, got %T", field, value)
+	}
+}
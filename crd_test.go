@@ -0,0 +1,311 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	api "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newDNSChaos(namespace, name string, generation int64, spec map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": dnsChaosGVR.GroupVersion().String(),
+		"kind":       "DNSChaos",
+		"spec":       spec,
+	}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetGeneration(generation)
+	return u
+}
+
+func newCRDTestWatcher(pods ...*api.Pod) (*Kubernetes, *crdWatcher) {
+	k := newGRPCTestKubernetes(pods...)
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{dnsChaosGVR: "DNSChaosList"})
+
+	return k, newCRDWatcher(k, client, "")
+}
+
+func testPod(namespace, name, ip string) *api.Pod {
+	return &api.Pod{
+		ObjectMeta: meta.ObjectMeta{Namespace: namespace, Name: name},
+		Status:     api.PodStatus{PodIP: ip},
+	}
+}
+
+func TestDNSChaosRequest(t *testing.T) {
+	tests := []struct {
+		spec       map[string]interface{}
+		pods       []string
+		dryRun     bool
+		randomMode string
+		shouldErr  bool
+	}{
+		{
+			spec: map[string]interface{}{
+				"action":   "error",
+				"patterns": []interface{}{"google.com"},
+				"pods": []interface{}{
+					map[string]interface{}{"name": "busybox-0"},
+					map[string]interface{}{"namespace": "other", "name": "busybox-1"},
+				},
+			},
+			pods: []string{"testns/busybox-0", "other/busybox-1"},
+		},
+		{
+			spec: map[string]interface{}{
+				"action":     "random",
+				"dryRun":     true,
+				"randomMode": "name",
+				"pods":       []interface{}{map[string]interface{}{"name": "busybox-0"}},
+			},
+			pods:       []string{"testns/busybox-0"},
+			dryRun:     true,
+			randomMode: RandomPerName,
+		},
+		{
+			spec: map[string]interface{}{
+				"action": "random",
+				"pods":   []interface{}{map[string]interface{}{"namespace": "testns"}},
+			},
+			shouldErr: true,
+		},
+		{
+			spec: map[string]interface{}{
+				"action": "random",
+				"pods":   []interface{}{"busybox-0"},
+			},
+			shouldErr: true,
+		},
+		{
+			spec: map[string]interface{}{
+				"action": "ttl",
+				"ttl":    int64(-1),
+				"pods":   []interface{}{map[string]interface{}{"name": "busybox-0"}},
+			},
+			shouldErr: true,
+		},
+		{
+			spec: map[string]interface{}{
+				"action":   "ttl",
+				"chaosTTL": int64(1 << 32),
+				"pods":     []interface{}{map[string]interface{}{"name": "busybox-0"}},
+			},
+			shouldErr: true,
+		},
+		{
+			spec: map[string]interface{}{
+				"action":    "partial",
+				"dropCount": int64(1 << 31),
+				"pods":      []interface{}{map[string]interface{}{"name": "busybox-0"}},
+			},
+			shouldErr: true,
+		},
+		{
+			spec: map[string]interface{}{
+				"action":    "mutate",
+				"mutateTTL": int64(-1),
+				"pods":      []interface{}{map[string]interface{}{"name": "busybox-0"}},
+			},
+			shouldErr: true,
+		},
+		{
+			spec: map[string]interface{}{
+				"action":     "cname_chain",
+				"cnameDepth": int64(-1),
+				"pods":       []interface{}{map[string]interface{}{"name": "busybox-0"}},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for i, tc := range tests {
+		req, err := dnsChaosRequest(newDNSChaos("testns", "chaos", 1, tc.spec))
+		if err != nil && !tc.shouldErr {
+			t.Fatalf("Test %d: Expected no error, got %q", i, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Fatalf("Test %d: Expected error, got none", i)
+		}
+		if err != nil {
+			continue
+		}
+
+		if req.Name != "crd/testns/chaos" {
+			t.Errorf("Test %d: Expected experiment name crd/testns/chaos, got %s", i, req.Name)
+		}
+		if req.DryRun != tc.dryRun {
+			t.Errorf("Test %d: Expected dry run %v, got %v", i, tc.dryRun, req.DryRun)
+		}
+		if req.RandomMode != tc.randomMode {
+			t.Errorf("Test %d: Expected random mode %q, got %q", i, tc.randomMode, req.RandomMode)
+		}
+		if len(req.Pods) != len(tc.pods) {
+			t.Fatalf("Test %d: Expected %d pods, got %d", i, len(tc.pods), len(req.Pods))
+		}
+		for j, pod := range req.Pods {
+			if got := pod.Namespace + "/" + pod.Name; got != tc.pods[j] {
+				t.Errorf("Test %d: Expected pod %s, got %s", i, tc.pods[j], got)
+			}
+		}
+	}
+}
+
+func TestDNSChaosRequestChaosTTL(t *testing.T) {
+	for i, tc := range []struct {
+		spec        map[string]interface{}
+		expectedSet bool
+	}{
+		{map[string]interface{}{"action": "random"}, false},
+		{map[string]interface{}{"action": "random", "chaosTTL": int64(0)}, true},
+	} {
+		req, err := dnsChaosRequest(newDNSChaos("testns", "chaos", 1, tc.spec))
+		if err != nil {
+			t.Fatalf("Test %d: Expected no error, got %q", i, err)
+		}
+		if req.GetChaosTtl().GetValue() != 0 || (req.ChaosTtl != nil) != tc.expectedSet {
+			t.Errorf("Test %d: Expected chaos TTL 0 set %v, got %v", i, tc.expectedSet, req.ChaosTtl)
+		}
+	}
+}
+
+func TestDNSChaosRequestDropFraction(t *testing.T) {
+	for i, tc := range []struct {
+		dropFraction interface{}
+		expected     float64
+		shouldErr    bool
+	}{
+		{float64(0.5), 0.5, false},
+		{int64(1), 1, false},
+		{"half", 0, true},
+	} {
+		spec := map[string]interface{}{"action": "partial", "dropFraction": tc.dropFraction}
+		req, err := dnsChaosRequest(newDNSChaos("testns", "chaos", 1, spec))
+		if err != nil && !tc.shouldErr {
+			t.Fatalf("Test %d: Expected no error, got %q", i, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Fatalf("Test %d: Expected error, got none", i)
+		}
+		if err == nil && req.DropFraction != tc.expected {
+			t.Errorf("Test %d: Expected drop fraction %v, got %v", i, tc.expected, req.DropFraction)
+		}
+	}
+}
+
+func TestCRDWatcher(t *testing.T) {
+	k, w := newCRDTestWatcher(testPod("testns", "busybox-0", "10.0.0.1"), testPod("testns", "busybox-1", "10.0.0.2"))
+
+	obj := newDNSChaos("testns", "chaos", 1, map[string]interface{}{
+		"action": "error",
+		"pods":   []interface{}{map[string]interface{}{"name": "busybox-0"}},
+	})
+	w.Add(obj)
+
+	if _, ok := k.chaosMap["crd/testns/chaos"]; !ok {
+		t.Fatalf("Expected experiment to be applied")
+	}
+	if podInfo := k.ipPodMap["10.0.0.1"]; podInfo == nil || podInfo.Action != ActionError {
+		t.Fatalf("Expected chaos on pod busybox-0, got %v", podInfo)
+	}
+
+	k.ipPodMap["10.0.0.1"].Experiment.hit(k.ipPodMap["10.0.0.1"])
+	status := w.status("crd/testns/chaos")
+	if status["active"] != true || status["hits"] != int64(1) {
+		t.Errorf("Expected active experiment with 1 hit, got %v", status)
+	}
+	if pods := status["matchedPods"].([]interface{}); len(pods) != 1 || pods[0] != "testns/busybox-0" {
+		t.Errorf("Expected matched pod testns/busybox-0, got %v", pods)
+	}
+
+	// a new generation replaces the targeted pods
+	newObj := newDNSChaos("testns", "chaos", 2, map[string]interface{}{
+		"action": "random",
+		"pods":   []interface{}{map[string]interface{}{"name": "busybox-1"}},
+	})
+	w.Update(obj, newObj)
+
+	if podInfo := k.ipPodMap["10.0.0.1"]; podInfo != nil {
+		t.Errorf("Expected chaos on pod busybox-0 to be removed, got %v", podInfo)
+	}
+	if podInfo := k.ipPodMap["10.0.0.2"]; podInfo == nil || podInfo.Action != ActionRandom {
+		t.Errorf("Expected random chaos on pod busybox-1, got %v", podInfo)
+	}
+
+	w.Delete(newObj)
+	if len(k.chaosMap) != 0 || len(k.ipPodMap) != 0 {
+		t.Errorf("Expected no chaos after delete, got %v, %v", k.chaosMap, k.ipPodMap)
+	}
+	if status := w.status("crd/testns/chaos"); status["active"] != false {
+		t.Errorf("Expected inactive experiment after delete, got %v", status)
+	}
+
+	// a pod which doesn't exist is reported in the status
+	w.Add(newDNSChaos("testns", "missing", 1, map[string]interface{}{
+		"action": "error",
+		"pods":   []interface{}{map[string]interface{}{"name": "missing"}},
+	}))
+	if status := w.status("crd/testns/missing"); status["message"] == nil {
+		t.Errorf("Expected error message in status, got %v", status)
+	}
+
+	// the failed object is retried on resync once the pod is created
+	missing := newDNSChaos("testns", "missing", 1, map[string]interface{}{
+		"action": "error",
+		"pods":   []interface{}{map[string]interface{}{"name": "missing"}},
+	})
+	if _, err := k.Client.Pods("testns").Create(context.Background(), testPod("testns", "missing", "10.0.0.3"), meta.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	w.Update(missing, missing)
+	if status := w.status("crd/testns/missing"); status["active"] != true || status["message"] != nil {
+		t.Errorf("Expected active experiment after resync, got %v", status)
+	}
+}
+
+func TestCRDWatcherReconcile(t *testing.T) {
+	k, w := newCRDTestWatcher(testPod("testns", "busybox-0", "10.0.0.1"), testPod("testns", "busybox-1", "10.0.0.2"))
+
+	kept := newDNSChaos("testns", "kept", 1, map[string]interface{}{
+		"action": "error",
+		"pods":   []interface{}{map[string]interface{}{"name": "busybox-0"}},
+	})
+	w.Add(kept)
+	w.Add(newDNSChaos("testns", "deleted", 1, map[string]interface{}{
+		"action": "error",
+		"pods":   []interface{}{map[string]interface{}{"name": "busybox-1"}},
+	}))
+	if len(k.chaosMap) != 2 {
+		t.Fatalf("Expected 2 experiments, got %v", k.chaosMap)
+	}
+
+	// only the kept object is listed by the informer after the watcher is rebuilt
+	if err := w.informer.GetStore().Add(kept); err != nil {
+		t.Fatal(err)
+	}
+	w.reconcile()
+
+	if _, ok := k.chaosMap["crd/testns/kept"]; !ok {
+		t.Errorf("Expected experiment of the kept object to be kept")
+	}
+	if _, ok := k.chaosMap["crd/testns/deleted"]; ok {
+		t.Errorf("Expected experiment of the deleted object to be canceled")
+	}
+	if podInfo := k.ipPodMap["10.0.0.2"]; podInfo != nil {
+		t.Errorf("Expected chaos on pod busybox-1 to be removed, got %v", podInfo)
+	}
+
+	// the experiments of the namespaces which aren't watched are left alone
+	w.namespace = "otherns"
+	w.informer.GetStore().Delete(kept)
+	w.reconcile()
+	if _, ok := k.chaosMap["crd/testns/kept"]; !ok {
+		t.Errorf("Expected experiment in the namespace which isn't watched to be kept")
+	}
+}
@@ -0,0 +1,207 @@
+package kubernetes
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coredns/coredns/plugin"
+	"github.com/coredns/coredns/plugin/dnstap/msg"
+	"github.com/coredns/coredns/request"
+
+	tap "github.com/dnstap/golang-dnstap"
+	"github.com/golang/protobuf/proto"
+	"github.com/miekg/dns"
+)
+
+// chaosTap writes the DNS messages of the pods which chaos is applied to in dnstap format. For every
+// DNS request it writes the query as CLIENT_QUERY and the answer the client received as CLIENT_RESPONSE.
+// When chaos is injected, the real answer is also written as AUTH_RESPONSE. The extra field of the
+// messages is the name of the experiment, which is empty for the chaos configured in Corefile.
+type chaosTap struct {
+	// endpoint is unix://PATH for a unix socket, or the path of a file
+	endpoint string
+
+	sync.RWMutex
+	output tap.Output
+}
+
+func newChaosTap(endpoint string) *chaosTap {
+	return &chaosTap{endpoint: endpoint}
+}
+
+// open connects to the socket or creates the file, the messages are dropped until it is opened
+func (t *chaosTap) open() error {
+	var output tap.Output
+	if strings.HasPrefix(t.endpoint, unixSocketPrefix) {
+		addr, err := net.ResolveUnixAddr("unix", strings.TrimPrefix(t.endpoint, unixSocketPrefix))
+		if err != nil {
+			return err
+		}
+		// the socket output reconnects by itself if the reader isn't ready
+		sockOutput, err := tap.NewFrameStreamSockOutput(addr)
+		if err != nil {
+			return err
+		}
+		output = sockOutput
+	} else {
+		fileOutput, err := tap.NewFrameStreamOutputFromFilename(t.endpoint)
+		if err != nil {
+			return err
+		}
+		output = fileOutput
+	}
+	go output.RunOutputLoop()
+
+	t.Lock()
+	t.output = output
+	t.Unlock()
+	return nil
+}
+
+// close flushes the buffered messages and closes the output
+func (t *chaosTap) close() error {
+	t.Lock()
+	output := t.output
+	t.output = nil
+	t.Unlock()
+
+	if output != nil {
+		output.Close()
+	}
+	return nil
+}
+
+// send writes a message without blocking, it is dropped if the output is falling behind
+func (t *chaosTap) send(podInfo *PodInfo, m *tap.Message) {
+	payload, err := proto.Marshal(&tap.Dnstap{
+		Type:    tap.Dnstap_MESSAGE.Enum(),
+		Message: m,
+		Extra:   []byte(experimentLabel(podInfo)),
+	})
+	if err != nil {
+		log.Warningf("fail to marshal dnstap message: %v", err)
+		return
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	if t.output == nil {
+		return
+	}
+	select {
+	case t.output.GetOutputChannel() <- payload:
+	default:
+		log.Debugf("dnstap output is full, drop the message")
+	}
+}
+
+// message builds a dnstap message of the given type for the packed DNS message
+func (t *chaosTap) message(state request.Request, typ tap.Message_Type, packed []byte) (*tap.Message, error) {
+	b := msg.New().Time(time.Now()).Addr(state.W.RemoteAddr())
+	b.Packed = packed
+
+	var (
+		m   *tap.Message
+		err error
+	)
+	if typ == tap.Message_CLIENT_QUERY {
+		m, err = b.ToClientQuery()
+	} else {
+		m, err = b.ToClientResponse()
+		m.Type = &typ
+	}
+	return m, err
+}
+
+// sendMsg writes a DNS message of the pod, a nil chaosTap writes nothing
+func (t *chaosTap) sendMsg(state request.Request, podInfo *PodInfo, typ tap.Message_Type, dnsMsg *dns.Msg) {
+	if t == nil || dnsMsg == nil {
+		return
+	}
+
+	packed, err := dnsMsg.Pack()
+	if err != nil {
+		log.Warningf("fail to pack dnstap message: %v", err)
+		return
+	}
+	t.sendPacked(state, podInfo, typ, packed)
+}
+
+func (t *chaosTap) sendPacked(state request.Request, podInfo *PodInfo, typ tap.Message_Type, packed []byte) {
+	m, err := t.message(state, typ, packed)
+	if err != nil {
+		log.Warningf("fail to build dnstap message: %v", err)
+		return
+	}
+	t.send(podInfo, m)
+}
+
+// tapWriter writes the responses sent to the client to dnstap as CLIENT_RESPONSE
+type tapWriter struct {
+	dns.ResponseWriter
+	tap     *chaosTap
+	state   request.Request
+	podInfo *PodInfo
+	written bool
+}
+
+// WriteMsg implements the dns.ResponseWriter interface.
+func (w *tapWriter) WriteMsg(m *dns.Msg) error {
+	w.written = true
+	w.tap.sendMsg(w.state, w.podInfo, tap.Message_CLIENT_RESPONSE, m)
+	return w.ResponseWriter.WriteMsg(m)
+}
+
+// Write implements the dns.ResponseWriter interface.
+func (w *tapWriter) Write(buf []byte) (int, error) {
+	w.written = true
+	w.tap.sendPacked(w.state, w.podInfo, tap.Message_CLIENT_RESPONSE, buf)
+	return w.ResponseWriter.Write(buf)
+}
+
+// finish records the failure which is written to the client by the server after the request is served
+func (w *tapWriter) finish(r *dns.Msg, rcode int) {
+	if w.written || plugin.ClientWrite(rcode) {
+		return
+	}
+
+	m := new(dns.Msg)
+	m.SetRcode(r, rcode)
+	w.tap.sendMsg(w.state, w.podInfo, tap.Message_CLIENT_RESPONSE, m)
+}
+
+// realResponse builds the answer the server would serve without chaos, it is nil if the
+// answer can't be built
+func (k *Kubernetes) realResponse(ctx context.Context, state request.Request, zone string, records, extra []dns.RR, err error) *dns.Msg {
+	if k.IsNameError(err) && (len(zone) == 0 || k.Fall.Through(state.Name())) {
+		// the request is served by the next plugins, resolve it through the whole server
+		resp, err := k.Lookup(withChaosLookup(ctx), state, state.QName(), state.QType())
+		if err != nil || resp == nil {
+			log.Debugf("fail to look up the real answer of %s %s: %v", state.Type(), state.QName(), err)
+			return nil
+		}
+		resp.Id = state.Req.Id
+		return resp
+	}
+
+	m := new(dns.Msg)
+	m.SetReply(state.Req)
+	m.Authoritative = true
+
+	switch {
+	case k.IsNameError(err) && !k.APIConn.HasSynced():
+		m.Rcode = dns.RcodeServerFailure
+	case k.IsNameError(err):
+		m.Rcode = dns.RcodeNameError
+	case err != nil:
+		return nil
+	default:
+		m.Answer = records
+		m.Extra = extra
+	}
+	return m
+}
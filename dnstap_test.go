@@ -0,0 +1,175 @@
+package kubernetes
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	tap "github.com/dnstap/golang-dnstap"
+	"github.com/golang/protobuf/proto"
+	"github.com/miekg/dns"
+)
+
+// readTap decodes the frames read by a dnstap reader until count messages are read
+func readTap(t *testing.T, frames chan []byte, count int) []*tap.Dnstap {
+	var messages []*tap.Dnstap
+	for len(messages) < count {
+		select {
+		case frame := <-frames:
+			d := &tap.Dnstap{}
+			if err := proto.Unmarshal(frame, d); err != nil {
+				t.Fatalf("Expected dnstap message, got error %v", err)
+			}
+			messages = append(messages, d)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d dnstap messages, got %d", count, len(messages))
+		}
+	}
+	return messages
+}
+
+func serveTapped(t *testing.T, k *Kubernetes, qname string) {
+	m := new(dns.Msg)
+	m.SetQuestion(qname, dns.TypeA)
+	k.ServeDNS(context.TODO(), dnstest.NewRecorder(&test.ResponseWriter{}), m)
+}
+
+func checkTap(t *testing.T, messages []*tap.Dnstap, expectedTypes []tap.Message_Type) {
+	if len(messages) != len(expectedTypes) {
+		t.Fatalf("Expected %d dnstap messages, got %d", len(expectedTypes), len(messages))
+	}
+	for i, d := range messages {
+		if d.Message.GetType() != expectedTypes[i] {
+			t.Errorf("Message %d: Expected type %v, got %v", i, expectedTypes[i], d.Message.GetType())
+		}
+		if string(d.Extra) != "chaos" {
+			t.Errorf("Message %d: Expected experiment chaos in extra, got %q", i, d.Extra)
+		}
+	}
+}
+
+func TestDnstapFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "chaos.dnstap")
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionRandom, Patterns: []string{"svc1.testns.*"}})
+	k.tap = newChaosTap(output)
+	if err := k.tap.open(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serveTapped(t, k, "svc1.testns.svc.cluster.local.")
+	// not matched, only the query and the real answer are written
+	req := &pb.SetDNSChaosRequest{Name: "chaos", Action: ActionRandom, Patterns: []string{"google.com"}, Pods: k.chaosMap["chaos"].Request.Pods}
+	if _, err := k.UpdateDNSChaos(context.TODO(), &pb.UpdateDNSChaosRequest{Chaos: req}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	serveTapped(t, k, "svc1.testns.svc.cluster.local.")
+	k.tap.close()
+
+	input, err := tap.NewFrameStreamInputFromFilename(output)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	frames := make(chan []byte, 8)
+	go input.ReadInto(frames)
+
+	messages := readTap(t, frames, 5)
+	checkTap(t, messages, []tap.Message_Type{
+		tap.Message_CLIENT_QUERY, tap.Message_AUTH_RESPONSE, tap.Message_CLIENT_RESPONSE,
+		tap.Message_CLIENT_QUERY, tap.Message_CLIENT_RESPONSE,
+	})
+
+	// the real answer and the chaos answer are different
+	real, chaos := new(dns.Msg), new(dns.Msg)
+	if err := real.Unpack(messages[1].Message.ResponseMessage); err != nil {
+		t.Fatal(err)
+	}
+	if err := chaos.Unpack(messages[2].Message.ResponseMessage); err != nil {
+		t.Fatal(err)
+	}
+	if len(real.Answer) != 1 || len(chaos.Answer) != 1 || real.Answer[0].String() == chaos.Answer[0].String() {
+		t.Errorf("Expected the chaos answer to differ from the real answer, got %v and %v", real.Answer, chaos.Answer)
+	}
+}
+
+func TestDnstapSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "dnstap.sock")
+	input, err := tap.NewFrameStreamSockInputFromPath(socket)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	frames := make(chan []byte, 8)
+	go input.ReadInto(frames)
+
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionError})
+	k.tap = newChaosTap(unixSocketPrefix + socket)
+	if err := k.tap.open(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	serveTapped(t, k, "svc1.testns.svc.cluster.local.")
+	// the messages are flushed when the tap is closed
+	k.tap.close()
+
+	messages := readTap(t, frames, 3)
+	checkTap(t, messages, []tap.Message_Type{tap.Message_CLIENT_QUERY, tap.Message_AUTH_RESPONSE, tap.Message_CLIENT_RESPONSE})
+
+	// the failure written by the server is recorded
+	chaos := new(dns.Msg)
+	if err := chaos.Unpack(messages[2].Message.ResponseMessage); err != nil {
+		t.Fatal(err)
+	}
+	if chaos.Rcode != dns.RcodeServerFailure {
+		t.Errorf("Expected SERVFAIL, got %s", dns.RcodeToString[chaos.Rcode])
+	}
+}
+
+func TestDnstapRealResponseOutsideZones(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "chaos.dnstap")
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionRandom})
+	k.tap = newChaosTap(output)
+	if err := k.tap.open(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the name is served by the next plugin, the real answer is resolved through the server
+	k.Next = plugin.HandlerFunc(func(ctx context.Context, w dns.ResponseWriter, r *dns.Msg) (int, error) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		m.Answer = []dns.RR{test.A("google.com.	300	IN	A	8.8.8.8")}
+		w.WriteMsg(m)
+		return dns.RcodeSuccess, nil
+	})
+	m := new(dns.Msg)
+	m.SetQuestion("google.com.", dns.TypeA)
+	k.ServeDNS(newChaosLookupContext(t, k), dnstest.NewRecorder(&test.ResponseWriter{}), m)
+	// the real answer isn't written if it can't be resolved
+	serveTapped(t, k, "google.com.")
+	k.tap.close()
+
+	input, err := tap.NewFrameStreamInputFromFilename(output)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	frames := make(chan []byte, 8)
+	go input.ReadInto(frames)
+
+	messages := readTap(t, frames, 5)
+	checkTap(t, messages, []tap.Message_Type{
+		tap.Message_CLIENT_QUERY, tap.Message_AUTH_RESPONSE, tap.Message_CLIENT_RESPONSE,
+		tap.Message_CLIENT_QUERY, tap.Message_CLIENT_RESPONSE,
+	})
+
+	real := new(dns.Msg)
+	if err := real.Unpack(messages[1].Message.ResponseMessage); err != nil {
+		t.Fatal(err)
+	}
+	if real.Id != m.Id || real.Rcode != dns.RcodeSuccess || len(real.Answer) != 1 || real.Answer[0].String() != "google.com.	300	IN	A	8.8.8.8" {
+		t.Errorf("Expected the answer of the next plugin as the real answer, got %v", real)
+	}
+}
@@ -0,0 +1,123 @@
+package kubernetes
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/request"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/miekg/dns"
+)
+
+// eventBufferSize is the count of events buffered for every subscriber, the events are dropped
+// when the buffer is full, so that a slow subscriber never blocks ServeDNS
+const eventBufferSize = 256
+
+// eventBroker fans out the chaos events to the subscribers of WatchDNSChaosEvents.
+type eventBroker struct {
+	sync.RWMutex
+	subscribers map[*eventSubscriber]struct{}
+}
+
+type eventSubscriber struct {
+	// dropped is placed first to be 8-byte aligned for atomic operations
+	dropped uint64
+
+	// experiments filters the events by experiment name, nil means all the experiments
+	experiments map[string]struct{}
+	ch          chan *pb.DNSChaosEvent
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{
+		subscribers: make(map[*eventSubscriber]struct{}),
+	}
+}
+
+func (b *eventBroker) subscribe(experiments []string) *eventSubscriber {
+	s := &eventSubscriber{
+		ch: make(chan *pb.DNSChaosEvent, eventBufferSize),
+	}
+	if len(experiments) != 0 {
+		s.experiments = make(map[string]struct{}, len(experiments))
+		for _, name := range experiments {
+			s.experiments[name] = struct{}{}
+		}
+	}
+
+	b.Lock()
+	b.subscribers[s] = struct{}{}
+	b.Unlock()
+
+	return s
+}
+
+func (b *eventBroker) unsubscribe(s *eventSubscriber) {
+	b.Lock()
+	delete(b.subscribers, s)
+	b.Unlock()
+}
+
+// hasSubscribers is used to skip building events when nobody is watching
+func (b *eventBroker) hasSubscribers() bool {
+	b.RLock()
+	defer b.RUnlock()
+
+	return len(b.subscribers) != 0
+}
+
+// publish sends the event to the subscribers interested in it without blocking
+func (b *eventBroker) publish(event *pb.DNSChaosEvent) {
+	b.RLock()
+	defer b.RUnlock()
+
+	for s := range b.subscribers {
+		if s.experiments != nil {
+			if _, ok := s.experiments[event.Experiment]; !ok {
+				continue
+			}
+		}
+
+		select {
+		case s.ch <- event:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
+
+// takeDropped returns the count of events dropped since the last call
+func (s *eventSubscriber) takeDropped() uint64 {
+	return atomic.SwapUint64(&s.dropped, 0)
+}
+
+// newChaosEvent builds the event of a DNS request matched by the experiment of the pod,
+// resp is nil if nothing is written to the client
+func newChaosEvent(state request.Request, podInfo *PodInfo, decision string, rcode int, resp *dns.Msg) *pb.DNSChaosEvent {
+	now, _ := ptypes.TimestampProto(time.Now())
+
+	event := &pb.DNSChaosEvent{
+		Time:     now,
+		Pod:      &pb.Pod{Namespace: podInfo.Namespace, Name: podInfo.Name},
+		Qname:    state.Name(),
+		Qtype:    state.Type(),
+		Action:   podInfo.Action,
+		Decision: decision,
+		Rcode:    dns.RcodeToString[rcode],
+	}
+	if podInfo.Experiment != nil {
+		event.Experiment = podInfo.Experiment.Name
+	}
+
+	if resp != nil {
+		event.Rcode = dns.RcodeToString[resp.Rcode]
+		for _, rr := range resp.Answer {
+			event.Answers = append(event.Answers, rr.String())
+		}
+	}
+
+	return event
+}
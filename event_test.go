@@ -0,0 +1,101 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+)
+
+func TestEventBroker(t *testing.T) {
+	b := newEventBroker()
+	all := b.subscribe(nil)
+	filtered := b.subscribe([]string{"b"})
+
+	for i := 0; i < eventBufferSize+2; i++ {
+		b.publish(&pb.DNSChaosEvent{Experiment: "a"})
+	}
+	b.publish(&pb.DNSChaosEvent{Experiment: "b"})
+
+	if len(all.ch) != eventBufferSize {
+		t.Errorf("Expected %d buffered events, got %d", eventBufferSize, len(all.ch))
+	}
+	if dropped := all.takeDropped(); dropped != 3 {
+		t.Errorf("Expected 3 dropped events, got %d", dropped)
+	}
+	if dropped := all.takeDropped(); dropped != 0 {
+		t.Errorf("Expected dropped count to be reset, got %d", dropped)
+	}
+
+	if len(filtered.ch) != 1 || (<-filtered.ch).Experiment != "b" {
+		t.Errorf("Expected only the event of experiment b")
+	}
+
+	b.unsubscribe(all)
+	b.unsubscribe(filtered)
+	if b.hasSubscribers() {
+		t.Errorf("Expected no subscribers")
+	}
+}
+
+func TestServeDNSPublishesEvents(t *testing.T) {
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionRandom})
+	s := k.events.subscribe([]string{"chaos"})
+	defer k.events.unsubscribe(s)
+
+	m := new(dns.Msg)
+	m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+	k.ServeDNS(context.TODO(), &test.ResponseWriter{}, m)
+
+	if len(s.ch) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(s.ch))
+	}
+	event := <-s.ch
+	if event.Experiment != "chaos" || event.Pod.Name != "client" || event.Qtype != "A" || event.Action != ActionRandom || event.Decision != decisionInjected {
+		t.Errorf("Unexpected event %v", event)
+	}
+	if event.Rcode != "NOERROR" || len(event.Answers) != 1 {
+		t.Errorf("Expected 1 answer with NOERROR, got %v", event)
+	}
+}
+
+func TestServeDNSPublishesEventsOfRealAnswers(t *testing.T) {
+	tests := []struct {
+		req              *pb.SetDNSChaosRequest
+		pause            bool
+		expectedDecision string
+	}{
+		{&pb.SetDNSChaosRequest{Action: ActionError, DryRun: true}, false, decisionDryRun},
+		{&pb.SetDNSChaosRequest{Action: ActionError}, true, decisionPaused},
+	}
+
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, tc.req)
+		if tc.pause {
+			if _, err := k.PauseAll(context.Background(), &pb.PauseAllRequest{}); err != nil {
+				t.Fatalf("Test %d: Expected no error, got %v", i, err)
+			}
+		}
+		s := k.events.subscribe(nil)
+
+		m := new(dns.Msg)
+		m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+		k.ServeDNS(context.TODO(), &test.ResponseWriter{}, m)
+		k.events.unsubscribe(s)
+
+		if len(s.ch) != 1 {
+			t.Fatalf("Test %d: Expected 1 event, got %d", i, len(s.ch))
+		}
+		event := <-s.ch
+		if event.Decision != tc.expectedDecision || event.Action != ActionError {
+			t.Errorf("Test %d: Expected decision %s of action error, got %v", i, tc.expectedDecision, event)
+		}
+		if event.Rcode != "NOERROR" || len(event.Answers) != 1 || !strings.HasSuffix(event.Answers[0], "10.0.0.1") {
+			t.Errorf("Test %d: Expected the real answer 10.0.0.1, got %v", i, event)
+		}
+	}
+}
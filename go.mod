@@ -2,16 +2,17 @@ module github.com/chaos-mesh/k8s_dns_chaos
 
 go 1.25
 
-
 require (
 	github.com/caddyserver/caddy v1.0.5
 	github.com/coredns/coredns v1.7.0
+	github.com/dnstap/golang-dnstap v0.2.0
 	github.com/golang/protobuf v1.5.2
 	github.com/miekg/dns v1.1.43
 	github.com/pingcap/tidb-tools v6.3.0+incompatible
 	github.com/prometheus/client_golang v1.11.1
 	golang.org/x/net v0.22.0
 	google.golang.org/grpc v1.56.3
+	google.golang.org/protobuf v1.27.1
 	k8s.io/api v0.22.2
 	k8s.io/apimachinery v0.22.2
 	k8s.io/client-go v0.22.2
@@ -24,6 +25,7 @@ require (
 	github.com/cespare/xxhash/v2 v2.1.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/evanphx/json-patch v4.11.0+incompatible // indirect
+	github.com/farsightsec/golang-framestream v0.0.0-20190425193708-fa4b164d59b8 // indirect
 	github.com/flynn/go-shlex v0.0.0-20150515145356-3f9db97f8568 // indirect
 	github.com/go-logr/logr v1.0.0 // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
@@ -54,7 +56,6 @@ require (
 	google.golang.org/api v0.58.0 // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/genproto v0.0.0-20210917145530-b395a37504d4 // indirect
-	google.golang.org/protobuf v1.27.1 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
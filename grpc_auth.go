@@ -0,0 +1,249 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authReloadInterval is the minimum interval of checking whether the certificate, client CA
+// and token files are changed
+const authReloadInterval = 10 * time.Second
+
+// watchedFiles tracks the modification time of a group of files, so that they are only
+// reloaded after any of them is changed
+type watchedFiles struct {
+	files     []string
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+// changed returns whether any file is modified since the last call, the files are checked
+// at most once every authReloadInterval
+func (f *watchedFiles) changed(now time.Time) bool {
+	if !f.lastCheck.IsZero() && now.Sub(f.lastCheck) < authReloadInterval {
+		return false
+	}
+	f.lastCheck = now
+
+	var latest time.Time
+	for _, file := range f.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			// keep the loaded content while the file is being rotated
+			log.Warningf("fail to stat %s: %v", file, err)
+			return false
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	if latest.Equal(f.modTime) {
+		return false
+	}
+	f.modTime = latest
+	return true
+}
+
+// certReloader serves the certificate and the client CA of the gRPC server, and reloads them
+// when the files are rotated.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	sync.Mutex
+	files     watchedFiles
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+func newCertReloader(certFile, keyFile, caFile string) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		files:    watchedFiles{files: []string{certFile, keyFile}},
+	}
+	if caFile != "" {
+		r.files.files = append(r.files.files, caFile)
+	}
+
+	r.files.changed(time.Now())
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("fail to load grpc certificate: %v", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.caFile != "" {
+		pem, err := ioutil.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("fail to load grpc client CA: %v", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in grpc client CA %s", r.caFile)
+		}
+	}
+
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	return nil
+}
+
+// config returns the TLS config for a new connection, the files are reloaded first if they are changed
+func (r *certReloader) config(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.files.changed(time.Now()) {
+		if err := r.load(); err != nil {
+			// keep serving the old certificate, the new files may be half written
+			log.Errorf("fail to reload grpc certificate: %v", err)
+		} else {
+			log.Infof("grpc certificate reloaded")
+		}
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*r.cert},
+		NextProtos:   []string{"h2"},
+	}
+	if r.clientCAs != nil {
+		config.ClientCAs = r.clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// tokenAuth checks the bearer token in the metadata of the gRPC requests, the token is
+// reloaded when the file is rotated.
+type tokenAuth struct {
+	tokenFile string
+
+	sync.Mutex
+	files watchedFiles
+	token []byte
+}
+
+func newTokenAuth(tokenFile string) (*tokenAuth, error) {
+	a := &tokenAuth{
+		tokenFile: tokenFile,
+		files:     watchedFiles{files: []string{tokenFile}},
+	}
+
+	a.files.changed(time.Now())
+	if err := a.load(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *tokenAuth) load() error {
+	content, err := ioutil.ReadFile(a.tokenFile)
+	if err != nil {
+		return fmt.Errorf("fail to load grpc token: %v", err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return fmt.Errorf("grpc token file %s is empty", a.tokenFile)
+	}
+	a.token = []byte(token)
+	return nil
+}
+
+func (a *tokenAuth) check(ctx context.Context) error {
+	a.Lock()
+	if a.files.changed(time.Now()) {
+		if err := a.load(); err != nil {
+			log.Errorf("fail to reload grpc token: %v", err)
+		}
+	}
+	token := a.token
+	a.Unlock()
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if !strings.HasPrefix(value, "Bearer ") {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(value, "Bearer ")), token) == 1 {
+			return nil
+		}
+	}
+
+	return status.Error(codes.Unauthenticated, "invalid bearer token")
+}
+
+// isHealthMethod returns whether the method belongs to the health service, which doesn't require the
+// token, so that it can be probed by the readiness checks of Kubernetes
+func isHealthMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, healthMethodPrefix)
+}
+
+func (a *tokenAuth) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isHealthMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	if err := a.check(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *tokenAuth) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isHealthMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	if err := a.check(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// grpcServerOptions returns the options of the gRPC server for the TLS and token settings in Corefile
+func (k *Kubernetes) grpcServerOptions() ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+
+	if k.grpcTLSCert != "" {
+		reloader, err := newCertReloader(k.grpcTLSCert, k.grpcTLSKey, k.grpcTLSClientCA)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{GetConfigForClient: reloader.config})))
+	}
+
+	if k.grpcTokenFile != "" {
+		auth, err := newTokenAuth(k.grpcTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.ChainUnaryInterceptor(auth.unaryInterceptor), grpc.ChainStreamInterceptor(auth.streamInterceptor))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,274 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// writeTestCert writes a certificate and its key signed by parent, or a self-signed one if parent is nil
+func writeTestCert(t *testing.T, dir, name, commonName string, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := template, interface{}(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	if err := ioutil.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, name+".key"), keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert.Leaf, _ = x509.ParseCertificate(der)
+	return cert
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	writeTestCert(t, dir, "server", "old", nil)
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+
+	r, err := newCertReloader(certFile, keyFile, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	config, _ := r.config(nil)
+	if config.ClientAuth != tls.NoClientCert {
+		t.Errorf("Expected no client certificate to be required without client CA")
+	}
+
+	// rotate the certificate
+	writeTestCert(t, dir, "server", "new", nil)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(certFile, later, later)
+	r.files.lastCheck = time.Time{}
+
+	config, _ = r.config(nil)
+	leaf, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf.Subject.CommonName != "new" {
+		t.Errorf("Expected the rotated certificate, got %s", leaf.Subject.CommonName)
+	}
+
+	if _, err := newCertReloader(certFile, keyFile, filepath.Join(dir, "missing.crt")); err == nil {
+		t.Errorf("Expected error for missing client CA, got none")
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := newTokenAuth(tokenFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		md            metadata.MD
+		expectedError bool
+	}{
+		{metadata.Pairs("authorization", "Bearer secret"), false},
+		{metadata.Pairs("authorization", "Bearer wrong"), true},
+		{metadata.Pairs("authorization", "secret"), true},
+		{nil, true},
+	}
+	for i, tc := range tests {
+		err := a.check(metadata.NewIncomingContext(context.Background(), tc.md))
+		if (err != nil) != tc.expectedError {
+			t.Errorf("Test %d: Expected error %v, got %v", i, tc.expectedError, err)
+		}
+		if err != nil && status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Test %d: Expected Unauthenticated, got %v", i, err)
+		}
+	}
+}
+
+func TestGRPCServerWithMTLSAndToken(t *testing.T) {
+	dir := t.TempDir()
+	ca := writeTestCert(t, dir, "ca", "ca", nil)
+	writeTestCert(t, dir, "server", "server", &ca)
+	client := writeTestCert(t, dir, "client", "client", &ca)
+	if err := ioutil.WriteFile(filepath.Join(dir, "token"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	k := New([]string{"cluster.local."})
+	k.grpcTLSCert, k.grpcTLSKey, k.grpcTLSClientCA = filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt")
+	k.grpcTokenFile = filepath.Join(dir, "token")
+
+	opts, err := k.grpcServerOptions()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(opts...)
+	pb.RegisterDNSServer(s, k)
+	go s.Serve(listener)
+	defer s.Stop()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+	dial := func(certs []tls.Certificate) pb.DNSClient {
+		creds := credentials.NewTLS(&tls.Config{ServerName: "localhost", RootCAs: roots, Certificates: certs})
+		conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(creds))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return pb.NewDNSClient(conn)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret")
+
+	if _, err := dial([]tls.Certificate{client}).ListDNSChaos(authCtx, &pb.ListDNSChaosRequest{}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, err := dial([]tls.Certificate{client}).ListDNSChaos(ctx, &pb.ListDNSChaosRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without token, got %v", err)
+	}
+	if _, err := dial(nil).ListDNSChaos(authCtx, &pb.ListDNSChaosRequest{}); err == nil {
+		t.Errorf("Expected error without client certificate, got none")
+	}
+}
+
+func TestGRPCHealthWithAuth(t *testing.T) {
+	dir := t.TempDir()
+	ca := writeTestCert(t, dir, "ca", "ca", nil)
+	writeTestCert(t, dir, "server", "server", &ca)
+	client := writeTestCert(t, dir, "client", "client", &ca)
+	if err := ioutil.WriteFile(filepath.Join(dir, "token"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// reserve the ports of the servers
+	var addresses []string
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addresses = append(addresses, listener.Addr().String())
+		listener.Close()
+	}
+
+	k := New([]string{"cluster.local."})
+	k.APIConn = &APIConnServeTest{}
+	k.grpcAddress, k.grpcHealthAddress = addresses[0], addresses[1]
+	k.grpcTLSCert, k.grpcTLSKey, k.grpcTLSClientCA = filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.crt")
+	k.grpcTokenFile = filepath.Join(dir, "token")
+	if err := k.CreateGRPCServer(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer k.StopGRPCServer()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+	creds := credentials.NewTLS(&tls.Config{ServerName: "localhost", RootCAs: roots, Certificates: []tls.Certificate{client}})
+	tlsConn, err := grpc.Dial(k.grpcAddress, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tlsConn.Close()
+	plainConn, err := grpc.Dial(k.grpcHealthAddress, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plainConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		conn *grpc.ClientConn
+	}{
+		// the token isn't required by the health service
+		{tlsConn},
+		// no TLS or token on the health address
+		{plainConn},
+	}
+
+	for i, tc := range tests {
+		var status healthpb.HealthCheckResponse_ServingStatus
+		for status != healthpb.HealthCheckResponse_SERVING && ctx.Err() == nil {
+			resp, err := healthpb.NewHealthClient(tc.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+			if err != nil {
+				t.Fatalf("Test %d: Expected no error, got %v", i, err)
+			}
+			status = resp.Status
+			time.Sleep(10 * time.Millisecond)
+		}
+		if status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("Test %d: Expected SERVING, got %v", i, status)
+		}
+	}
+
+	// the other methods still require the token
+	if _, err := pb.NewDNSClient(tlsConn).ListDNSChaos(ctx, &pb.ListDNSChaosRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without token, got %v", err)
+	}
+	// and the health address serves nothing else
+	if _, err := pb.NewDNSClient(plainConn).ListDNSChaos(ctx, &pb.ListDNSChaosRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented on the health address, got %v", err)
+	}
+}
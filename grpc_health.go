@@ -0,0 +1,56 @@
+package kubernetes
+
+import (
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// dnsServiceName is the full name of the chaos gRPC service, which health is reported for
+	// besides the overall health of the server
+	dnsServiceName = "pb.DNS"
+
+	// healthMethodPrefix is the prefix of the full names of the methods of the health service
+	healthMethodPrefix = "/grpc.health.v1.Health/"
+
+	// healthCheckInterval is the interval of updating the health of the gRPC server
+	healthCheckInterval = time.Second
+)
+
+// servingStatus returns NOT_SERVING until the informers are synced and while the IP of any pod
+// of experiments fails to be refreshed
+func (k *Kubernetes) servingStatus() healthpb.HealthCheckResponse_ServingStatus {
+	if k.APIConn == nil || !k.APIConn.HasSynced() {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	// a pod is retried here, otherwise it is only refreshed when it sends a DNS request
+	for _, podInfo := range k.podsToRetry(time.Now()) {
+		k.refreshPod(podInfo)
+	}
+	if len(k.failedPods()) != 0 {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+// updateHealth updates the health of the gRPC server until stopCh is closed
+func (k *Kubernetes) updateHealth(s *health.Server, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		status := k.servingStatus()
+		s.SetServingStatus("", status)
+		s.SetServingStatus(dnsServiceName, status)
+
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
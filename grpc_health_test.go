@@ -0,0 +1,155 @@
+package kubernetes
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestServingStatus(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(testPod("testns", "busybox-0", "10.0.0.1"))
+	k := New([]string{"cluster.local."})
+	k.Client = client.CoreV1()
+
+	k.APIConn = &APIConnServeTest{notSynced: true}
+	if status := k.servingStatus(); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected NOT_SERVING before synced, got %v", status)
+	}
+	k.APIConn = &APIConnServeTest{}
+	if status := k.servingStatus(); status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING after synced, got %v", status)
+	}
+
+	req := &pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Pods: []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}}}
+	if _, err := k.SetDNSChaos(ctx, req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the pod is gone when its IP is refreshed
+	if err := client.CoreV1().Pods("testns").Delete(ctx, "busybox-0", meta.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	k.ipPodMap["10.0.0.1"].LastUpdateTime = time.Time{}
+	if _, err := k.getChaosPod("10.0.0.1"); err == nil {
+		t.Fatalf("Expected error for the deleted pod, got none")
+	}
+	if status := k.servingStatus(); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected NOT_SERVING while pod resolution is failing, got %v", status)
+	}
+
+	// the failed pod isn't retried until its backoff expires
+	actions := len(client.Actions())
+	for i := 0; i < 3; i++ {
+		k.servingStatus()
+	}
+	if len(client.Actions()) != actions {
+		t.Errorf("Expected no API request during the backoff, got %v", client.Actions()[actions:])
+	}
+
+	// the pod is recreated with another IP
+	if _, err := client.CoreV1().Pods("testns").Create(ctx, testPod("testns", "busybox-0", "10.0.0.2"), meta.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if status := k.servingStatus(); status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected NOT_SERVING before the pod is retried, got %v", status)
+	}
+	if pods := k.podsToRetry(time.Now().Add(podRetryInterval)); len(pods) != 1 {
+		t.Errorf("Expected the pod to be retried after %v, got %v", podRetryInterval, pods)
+	}
+	k.resolveFailures[k.ipPodMap["10.0.0.1"]].nextRetry = time.Time{}
+	if status := k.servingStatus(); status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING after pod resolution recovered, got %v", status)
+	}
+	if podInfo, _ := k.getChaosPod("10.0.0.2"); podInfo == nil {
+		t.Errorf("Expected the pod to be found by its new IP")
+	}
+
+	// the failures of released pods are forgotten
+	if err := client.CoreV1().Pods("testns").Delete(ctx, "busybox-0", meta.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	k.ipPodMap["10.0.0.2"].LastUpdateTime = time.Time{}
+	k.getChaosPod("10.0.0.2")
+	if _, err := k.CancelDNSChaos(ctx, &pb.CancelDNSChaosRequest{Name: "a"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status := k.servingStatus(); status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING after the experiment is canceled, got %v", status)
+	}
+}
+
+func TestGRPCHealthAndReflection(t *testing.T) {
+	k := New([]string{"cluster.local."})
+	k.APIConn = &APIConnServeTest{}
+	k.grpcAddress = unixSocketPrefix + filepath.Join(t.TempDir(), "chaos.sock")
+	if err := k.CreateGRPCServer(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer k.StopGRPCServer()
+
+	conn, err := grpc.Dial(k.grpcAddress, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, service := range []string{"", dnsServiceName} {
+		var status healthpb.HealthCheckResponse_ServingStatus
+		for status != healthpb.HealthCheckResponse_SERVING && ctx.Err() == nil {
+			resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			status = resp.Status
+			time.Sleep(10 * time.Millisecond)
+		}
+		if status != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("Expected service %q to be SERVING, got %v", service, status)
+		}
+	}
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	services := make(map[string]bool)
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services[service.Name] = true
+	}
+	for _, name := range []string{dnsServiceName, "grpc.health.v1.Health"} {
+		if !services[name] {
+			t.Errorf("Expected service %s to be listed by reflection, got %v", name, services)
+		}
+	}
+
+	if err := stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: dnsServiceName}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resp.GetFileDescriptorResponse().GetFileDescriptorProto()) == 0 {
+		t.Errorf("Expected the descriptor of %s, got %v", dnsServiceName, resp)
+	}
+}
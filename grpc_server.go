@@ -4,30 +4,115 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/caddyserver/caddy"
 	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/miekg/dns"
 	trieselector "github.com/pingcap/tidb-tools/pkg/table-rule-selector"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// CreateGRPCServer ...
-func (k Kubernetes) CreateGRPCServer() error {
-	if k.grpcPort == 0 {
+const (
+	defaultGRPCPort = 9288
+
+	// grpcStopTimeout is the time to wait for the running requests when the grpc server is stopped,
+	// the server is stopped forcibly after it, because the event streams never end by themselves
+	grpcStopTimeout = 5 * time.Second
+)
+
+// grpcListenAddress returns the address the grpc server listens on, in the form of [HOST]:PORT or unix://PATH
+func (k *Kubernetes) grpcListenAddress() string {
+	if k.grpcAddress != "" {
+		return k.grpcAddress
+	}
+
+	port := k.grpcPort
+	if port == 0 {
 		// use default port
-		k.grpcPort = 9288
+		port = defaultGRPCPort
+	}
+	return fmt.Sprintf(":%d", port)
+}
 
+// CreateGRPCServer starts the grpc server, it is called on startup and when a Corefile reload failed
+func (k *Kubernetes) CreateGRPCServer() error {
+	address := k.grpcListenAddress()
+	log.Infof("CreateGRPCServer on %s", address)
+
+	opts, err := k.grpcServerOptions()
+	if err != nil {
+		return err
+	}
+	// the metrics interceptors are the outermost, so that the rejected requests are recorded too
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(metricsUnaryInterceptor),
+		grpc.ChainStreamInterceptor(metricsStreamInterceptor),
+	}, opts...)
+
+	network := "tcp"
+	if strings.HasPrefix(address, unixSocketPrefix) {
+		network = "unix"
+		address = strings.TrimPrefix(address, unixSocketPrefix)
+		// the socket file is left if the process exited without stopping the server
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 	}
-	log.Infof("CreateGRPCServer on port %d", k.grpcPort)
 
-	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", k.grpcPort))
+	grpcListener, err := net.Listen(network, address)
 	if err != nil {
 		return err
 	}
 
-	s := grpc.NewServer()
+	var healthListener net.Listener
+	if k.grpcHealthAddress != "" {
+		healthListener, err = net.Listen("tcp", k.grpcHealthAddress)
+		if err != nil {
+			grpcListener.Close()
+			return err
+		}
+	}
+
+	s := grpc.NewServer(opts...)
 	pb.RegisterDNSServer(s, k)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus(dnsServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s, healthServer)
+	reflection.Register(s)
+
+	if healthListener != nil {
+		hs := grpc.NewServer()
+		healthpb.RegisterHealthServer(hs, healthServer)
+		k.grpcHealthServer = hs
+		go func() {
+			if err := hs.Serve(healthListener); err != nil {
+				log.Errorf("grpc health serve error %v", err)
+			}
+		}()
+	}
+
+	k.grpcServer = s
+	k.grpcListeners = []net.Listener{grpcListener}
+	if healthListener != nil {
+		k.grpcListeners = append(k.grpcListeners, healthListener)
+	}
+	k.grpcHealth = healthServer
+	k.grpcHealthStop = make(chan struct{})
+	go k.updateHealth(healthServer, k.grpcHealthStop)
 	go func() {
 		if err := s.Serve(grpcListener); err != nil {
 			log.Errorf("grpc serve error %v", err)
@@ -38,46 +123,379 @@ func (k Kubernetes) CreateGRPCServer() error {
 	return nil
 }
 
+// StopGRPCServer stops the grpc server gracefully, it is called on shutdown and before Corefile is reloaded
+func (k *Kubernetes) StopGRPCServer() error {
+	s := k.grpcServer
+	if s == nil {
+		return nil
+	}
+	k.grpcServer = nil
+
+	close(k.grpcHealthStop)
+	// report NOT_SERVING to the health watchers before they are disconnected
+	k.grpcHealth.Shutdown()
+	if k.grpcHealthServer != nil {
+		// only the health is served, nothing is waited for
+		k.grpcHealthServer.Stop()
+		k.grpcHealthServer = nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(grpcStopTimeout):
+		log.Warningf("grpc server is not stopped in %v, stop it forcibly", grpcStopTimeout)
+		s.Stop()
+	}
+	for _, l := range k.grpcListeners {
+		// they are closed already if the servers started serving on them
+		l.Close()
+	}
+	k.grpcListeners = nil
+	return nil
+}
+
+// RegisterGRPCServer registers the grpc server start and stop functions with Caddy. Before Corefile is
+// reloaded, the server is stopped to release the address and the chaos state is stashed, the new instance
+// takes over the state and starts its own server.
+func (k *Kubernetes) RegisterGRPCServer(c *caddy.Controller) {
+	c.OnStartup(k.CreateGRPCServer)
+
+	c.OnRestart(func() error {
+		k.stashChaosState()
+		return k.StopGRPCServer()
+	})
+
+	c.OnRestartFailed(k.restartFailed)
+
+	c.OnShutdown(func() error {
+		k.dropStashedChaosState()
+		return k.StopGRPCServer()
+	})
+}
+
+// restartFailed keeps this instance serving after a failed reload, the changes of the new instance to the
+// chaos state are rolled back and its grpc server is stopped before the server of this instance starts again.
+func (k *Kubernetes) restartFailed() error {
+	k.rollBackChaosState()
+	k.dropStashedChaosState()
+	return k.CreateGRPCServer()
+}
+
 // SetDNSChaos ...
-func (k Kubernetes) SetDNSChaos(ctx context.Context, req *pb.SetDNSChaosRequest) (*pb.DNSChaosResponse, error) {
+func (k *Kubernetes) SetDNSChaos(ctx context.Context, req *pb.SetDNSChaosRequest) (_ *pb.DNSChaosResponse, err error) {
 	log.Infof("receive SetDNSChaos request %v", req)
+	defer func() { k.audit.control(ctx, "SetDNSChaos", req.Name, req, err) }()
+
+	if err := validateChaosRequest(req); err != nil {
+		return nil, err
+	}
+	pods, err := k.resolveRequestPods(req)
+	if err != nil {
+		return nil, err
+	}
+
+	k.Lock()
+	defer k.Unlock()
+
+	experiment := &Experiment{
+		Name:       req.Name,
+		Request:    req,
+		CreateTime: time.Now(),
+	}
+	if oldExperiment, ok := k.chaosMap[req.Name]; ok {
+		// the generation keeps increasing, so that a stale generation of the replaced experiment
+		// can't be reused by UpdateDNSChaos
+		experiment.Generation = oldExperiment.Generation
+	}
+	if err := k.applyExperiment(experiment, req, pods); err != nil {
+		return nil, err
+	}
+	if oldExperiment, ok := k.chaosMap[req.Name]; ok {
+		// release the pods which the experiment with the same name targeted but the request doesn't
+		k.releasePods(oldExperiment)
+		activeExperiments.WithLabelValues(oldExperiment.Request.Action).Dec()
+		deleteExperimentMetrics(oldExperiment)
+	}
+	k.chaosMap[req.Name] = experiment
+	activeExperiments.WithLabelValues(req.Action).Inc()
+
+	return &pb.DNSChaosResponse{
+		Result:     true,
+		Msg:        fmt.Sprintf("experiment %s is applied to %d pods", req.Name, len(req.Pods)),
+		Generation: experiment.Generation,
+	}, nil
+}
+
+// UpdateDNSChaos replaces the action, patterns and pods of an existing experiment in place,
+// the hits and create time of the experiment are kept.
+func (k *Kubernetes) UpdateDNSChaos(ctx context.Context, req *pb.UpdateDNSChaosRequest) (_ *pb.DNSChaosResponse, err error) {
+	log.Infof("receive UpdateDNSChaos request %v", req)
+	defer func() { k.audit.control(ctx, "UpdateDNSChaos", req.GetChaos().GetName(), req, err) }()
+
+	if req.Chaos == nil {
+		return nil, status.Error(codes.InvalidArgument, "chaos is required")
+	}
+	if err := validateChaosRequest(req.Chaos); err != nil {
+		return nil, err
+	}
+	pods, err := k.resolveRequestPods(req.Chaos)
+	if err != nil {
+		return nil, err
+	}
 
 	k.Lock()
 	defer k.Unlock()
 
-	k.chaosMap[req.Name] = req
+	experiment, ok := k.chaosMap[req.Chaos.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "experiment %s not found", req.Chaos.Name)
+	}
+
+	// generation 0 means the caller doesn't care about concurrent updates
+	if req.Generation != 0 && req.Generation != experiment.Generation {
+		return nil, status.Errorf(codes.Aborted, "experiment %s is at generation %d, not %d", req.Chaos.Name, experiment.Generation, req.Generation)
+	}
 
-	var scope string
-	if len(req.Patterns) == 0 {
+	oldAction := experiment.Request.Action
+	if err := k.applyExperiment(experiment, req.Chaos, pods); err != nil {
+		return nil, err
+	}
+	activeExperiments.WithLabelValues(oldAction).Dec()
+	activeExperiments.WithLabelValues(req.Chaos.Action).Inc()
+
+	return &pb.DNSChaosResponse{
+		Result:     true,
+		Msg:        fmt.Sprintf("experiment %s is updated to generation %d", req.Chaos.Name, experiment.Generation),
+		Generation: experiment.Generation,
+	}, nil
+}
+
+// validateChaosRequest checks the request before anything is changed
+func validateChaosRequest(req *pb.SetDNSChaosRequest) error {
+	if req.Name == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+	if !isValidAction(req.Action) {
+		return status.Errorf(codes.InvalidArgument, "unknown action %q, expected one of %s", req.Action, strings.Join(actions, ", "))
+	}
+	if !isValidScope(req.Scope) {
+		return status.Errorf(codes.InvalidArgument, "unknown scope %q, expected one of %s, %s, %s", req.Scope, ScopeInner, ScopeOuter, ScopeAll)
+	}
+	if !isValidProtocol(req.Protocol) {
+		return status.Errorf(codes.InvalidArgument, "unknown protocol %q, expected %s or %s", req.Protocol, ProtocolUDP, ProtocolTCP)
+	}
+	if _, err := parseQTypes(req.Qtypes); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !isValidMalformedVariant(req.MalformedVariant) {
+		return status.Errorf(codes.InvalidArgument, "unknown malformed variant %q, expected one of %s", req.MalformedVariant, strings.Join(malformedVariants, ", "))
+	}
+	for _, mode := range req.MutateModes {
+		if !isValidMutateMode(mode) {
+			return status.Errorf(codes.InvalidArgument, "unknown mutate mode %q, expected one of %s", mode, strings.Join(mutateModes, ", "))
+		}
+	}
+	if req.DropCount < 0 {
+		return status.Errorf(codes.InvalidArgument, "drop_count must not be negative, got %d", req.DropCount)
+	}
+	if req.DropFraction < 0 || req.DropFraction > 1 {
+		return status.Errorf(codes.InvalidArgument, "drop_fraction must be between 0 and 1, got %v", req.DropFraction)
+	}
+	for _, ip := range req.DropIps {
+		if net.ParseIP(ip) == nil {
+			return status.Errorf(codes.InvalidArgument, "invalid IP %q in drop_ips", ip)
+		}
+	}
+	if req.HotspotIp != "" && net.ParseIP(req.HotspotIp) == nil {
+		return status.Errorf(codes.InvalidArgument, "invalid hotspot_ip %q", req.HotspotIp)
+	}
+	if req.Action == ActionMisroute && len(req.Misroute) == 0 {
+		return status.Errorf(codes.InvalidArgument, "misroute is required by action %s", ActionMisroute)
+	}
+	if _, err := parseMisroute(req.Misroute); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.MutateTtl > maxTTL {
+		return status.Errorf(codes.InvalidArgument, "mutate_ttl must not be greater than %d, got %d", maxTTL, req.MutateTtl)
+	}
+	if req.Ttl > maxTTL {
+		return status.Errorf(codes.InvalidArgument, "ttl must not be greater than %d, got %d", maxTTL, req.Ttl)
+	}
+	if req.GetChaosTtl().GetValue() > maxTTL {
+		return status.Errorf(codes.InvalidArgument, "chaos_ttl must not be greater than %d, got %d", maxTTL, req.ChaosTtl.Value)
+	}
+	if !isValidRandomMode(req.RandomMode) {
+		return status.Errorf(codes.InvalidArgument, "unknown random mode %q, expected one of %s, %s, %s", req.RandomMode, RandomPerQuery, RandomPerName, RandomPerPod)
+	}
+	if req.Action == ActionCNAME && req.CnameTarget == "" {
+		return status.Errorf(codes.InvalidArgument, "cname_target is required by action %s", ActionCNAME)
+	}
+	if _, ok := dns.IsDomainName(req.CnameTarget); req.CnameTarget != "" && !ok {
+		return status.Errorf(codes.InvalidArgument, "invalid cname_target %q", req.CnameTarget)
+	}
+	if req.CnameDepth < 0 || req.CnameDepth > maxCNAMEDepth {
+		return status.Errorf(codes.InvalidArgument, "cname_depth must be between 0 and %d, got %d", maxCNAMEDepth, req.CnameDepth)
+	}
+
+	pods := make(map[string]struct{}, len(req.Pods))
+	for i, pod := range req.Pods {
+		if pod == nil || pod.Namespace == "" || pod.Name == "" {
+			return status.Errorf(codes.InvalidArgument, "pods[%d]: namespace and name are required", i)
+		}
+		key := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		if _, ok := pods[key]; ok {
+			return status.Errorf(codes.InvalidArgument, "pod %s is duplicated", key)
+		}
+		pods[key] = struct{}{}
+	}
+
+	for i, pattern := range req.Patterns {
+		if pattern == "" {
+			return status.Errorf(codes.InvalidArgument, "patterns[%d] is empty", i)
+		}
+	}
+
+	return nil
+}
+
+// podLookupError converts the error of getting a pod from the cluster to a grpc status
+func podLookupError(pod *pb.Pod, err error) error {
+	if apierrors.IsNotFound(err) {
+		return status.Errorf(codes.NotFound, "pod %s/%s not found", pod.Namespace, pod.Name)
+	}
+	return status.Errorf(codes.Unavailable, "fail to get pod %s/%s: %v", pod.Namespace, pod.Name, err)
+}
+
+// releasePods removes chaos from the pods of the experiment. The pods taken over by other experiments
+// are kept. The caller should hold the lock.
+func (k *Kubernetes) releasePods(experiment *Experiment) {
+	for _, podInfo := range k.experimentPods(experiment) {
+		delete(k.podMap[podInfo.Namespace], podInfo.Name)
+		delete(k.ipPodMap, podInfo.IP)
+	}
+}
+
+// requestPods are the pods of a request resolved from the cluster, they are resolved before the lock
+// is held so that the requests to the API server don't block serving DNS requests
+type requestPods struct {
+	// ips are the IPs of the pods of the request, in the same order
+	ips []string
+	// namespaceSizes are the counts of pods in the namespaces of the request, they are only listed
+	// if max_chaos_namespace_percent is set
+	namespaceSizes map[string]int
+}
+
+// resolveRequestPods gets the pods of the request and lists the pods in their namespaces if needed
+// by checkBlastRadius. It is called without the lock.
+func (k *Kubernetes) resolveRequestPods(req *pb.SetDNSChaosRequest) (*requestPods, error) {
+	pods := &requestPods{
+		ips:            make([]string, 0, len(req.Pods)),
+		namespaceSizes: make(map[string]int),
+	}
+
+	for _, pod := range req.Pods {
+		v1Pod, err := k.getPodFromCluster(pod.Namespace, pod.Name)
+		if err != nil {
+			log.Errorf("fail to getPodFromCluster %v", err)
+			return nil, podLookupError(pod, err)
+		}
+		if v1Pod.Status.PodIP == "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "pod %s/%s has no IP yet", pod.Namespace, pod.Name)
+		}
+		pods.ips = append(pods.ips, v1Pod.Status.PodIP)
+	}
+
+	if k.maxChaosNamespacePercent != 0 {
+		for _, pod := range req.Pods {
+			if _, ok := pods.namespaceSizes[pod.Namespace]; ok {
+				continue
+			}
+
+			list, err := k.Client.Pods(pod.Namespace).List(context.Background(), meta.ListOptions{})
+			if err != nil {
+				return nil, status.Errorf(codes.Unavailable, "fail to list pods in namespace %s: %v", pod.Namespace, err)
+			}
+			pods.namespaceSizes[pod.Namespace] = len(list.Items)
+		}
+	}
+
+	return pods, nil
+}
+
+// applyExperiment applies the request to the pods of the experiment, the pods targeted by the
+// experiment before but not by the request are released. The pods are resolved before, so that
+// a failed request leaves the experiment as it was. The caller should hold the lock.
+func (k *Kubernetes) applyExperiment(experiment *Experiment, req *pb.SetDNSChaosRequest, pods *requestPods) error {
+	scope := req.Scope
+	if scope == "" {
 		scope = ScopeAll
 	}
 
-	// build selector
-	selector := trieselector.NewTrieSelector()
+	// build selector, all the names are matched without patterns
+	var selector trieselector.Selector
+	if len(req.Patterns) != 0 {
+		selector = trieselector.NewTrieSelector()
+	}
 	for _, pattern := range req.Patterns {
 		err := selector.Insert(pattern, "", true, trieselector.Insert)
 		if err != nil {
-			log.Errorf("fail to build selector %v", err)
-			return nil, err
+			return status.Errorf(codes.InvalidArgument, "invalid pattern %q: %v", pattern, err)
 		}
 
 		if !strings.Contains(pattern, "*") {
 			// when send dns request to the dns server, will add a '.' at the end of the domain name.
 			err := selector.Insert(fmt.Sprintf("%s.", pattern), "", true, trieselector.Insert)
 			if err != nil {
-				log.Errorf("fail to build selector %v", err)
-				return nil, err
+				return status.Errorf(codes.InvalidArgument, "invalid pattern %q: %v", pattern, err)
 			}
 		}
 	}
 
-	for _, pod := range req.Pods {
-		v1Pod, err := k.getPodFromCluster(pod.Namespace, pod.Name)
-		if err != nil {
-			log.Errorf("fail to getPodFromCluster %v", err)
-			return nil, err
+	// the query types are validated before
+	qtypes, _ := parseQTypes(req.Qtypes)
+
+	var cnameTarget string
+	if req.CnameTarget != "" {
+		cnameTarget = dns.Fqdn(req.CnameTarget)
+	}
+
+	// the IPs are validated before, they are normalized to match the records
+	dropIPs := make([]string, 0, len(req.DropIps))
+	for _, ip := range req.DropIps {
+		dropIPs = append(dropIPs, net.ParseIP(ip).String())
+	}
+	var hotspotIP string
+	if req.HotspotIp != "" {
+		hotspotIP = net.ParseIP(req.HotspotIp).String()
+	}
+	// the mapping is validated before
+	misroute, _ := parseMisroute(req.Misroute)
+
+	if err := k.checkBlastRadius(experiment, req, pods.namespaceSizes); err != nil {
+		return err
+	}
+	podIPs := pods.ips
+
+	if experiment.Request != nil {
+		targeted := make(map[string]struct{}, len(req.Pods))
+		for _, pod := range req.Pods {
+			targeted[fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)] = struct{}{}
 		}
+		for _, podInfo := range k.experimentPods(experiment) {
+			if _, ok := targeted[fmt.Sprintf("%s/%s", podInfo.Namespace, podInfo.Name)]; !ok {
+				delete(k.podMap[podInfo.Namespace], podInfo.Name)
+				delete(k.ipPodMap, podInfo.IP)
+			}
+		}
+	}
 
+	for i, pod := range req.Pods {
 		if _, ok := k.podMap[pod.Namespace]; !ok {
 			k.podMap[pod.Namespace] = make(map[string]*PodInfo)
 		}
@@ -88,44 +506,76 @@ func (k Kubernetes) SetDNSChaos(ctx context.Context, req *pb.SetDNSChaosRequest)
 			delete(k.ipPodMap, oldPod.IP)
 		}
 
+		// the pod info is replaced rather than modified, ServeDNS may be reading the old one
 		podInfo := &PodInfo{
-			Namespace:      pod.Namespace,
-			Name:           pod.Name,
-			Action:         req.Action,
-			Scope:          scope,
-			Selector:       selector,
-			IP:             v1Pod.Status.PodIP,
-			LastUpdateTime: time.Now(),
+			Namespace:        pod.Namespace,
+			Name:             pod.Name,
+			Action:           req.Action,
+			Scope:            scope,
+			Selector:         selector,
+			IP:               podIPs[i],
+			LastUpdateTime:   time.Now(),
+			DryRun:           req.DryRun,
+			RandomMode:       req.RandomMode,
+			CNAMETarget:      cnameTarget,
+			CNAMEDepth:       int(req.CnameDepth),
+			Protocol:         req.Protocol,
+			QTypes:           qtypes,
+			TruncatePartial:  req.TruncatePartial,
+			TruncateTCPFail:  req.TruncateTcpFail,
+			MalformedVariant: req.MalformedVariant,
+			MalformedSeed:    req.MalformedSeed,
+			MutateModes:      req.MutateModes,
+			MutateTTL:        req.MutateTtl,
+			DropCount:        int(req.DropCount),
+			DropFraction:     req.DropFraction,
+			DropIPs:          dropIPs,
+			HotspotIP:        hotspotIP,
+			Misroute:         misroute,
+			TTL:              req.Ttl,
+			ChaosTTL:         req.GetChaosTtl().GetValue(),
+			ChaosTTLSet:      req.ChaosTtl != nil,
+			Experiment:       experiment,
 		}
 
 		k.podMap[pod.Namespace][pod.Name] = podInfo
-		k.ipPodMap[v1Pod.Status.PodIP] = podInfo
+		k.ipPodMap[podIPs[i]] = podInfo
 	}
 
-	return &pb.DNSChaosResponse{
-		Result: true,
-	}, nil
+	experiment.Request = req
+	experiment.Generation++
+
+	return nil
 }
 
 // CancelDNSChaos ...
-func (k Kubernetes) CancelDNSChaos(ctx context.Context, req *pb.CancelDNSChaosRequest) (*pb.DNSChaosResponse, error) {
+func (k *Kubernetes) CancelDNSChaos(ctx context.Context, req *pb.CancelDNSChaosRequest) (_ *pb.DNSChaosResponse, err error) {
 	log.Infof("receive CancelDNSChaos request %v", req)
+	defer func() { k.audit.control(ctx, "CancelDNSChaos", req.Name, req, err) }()
+
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
 	k.Lock()
 	defer k.Unlock()
 
-	if _, ok := k.chaosMap[req.Name]; !ok {
+	experiment, ok := k.chaosMap[req.Name]
+	if !ok {
+		// canceling is idempotent, so that the caller can retry it safely
 		return &pb.DNSChaosResponse{
 			Result: true,
+			Msg:    fmt.Sprintf("experiment %s doesn't exist", req.Name),
 		}, nil
 	}
 
-	for _, pod := range k.chaosMap[req.Name].Pods {
-		if _, ok := k.podMap[pod.Namespace]; ok {
-			if podInfo, ok := k.podMap[pod.Namespace][pod.Name]; ok {
-				delete(k.podMap[pod.Namespace], pod.Name)
-				delete(k.ipPodMap, podInfo.IP)
-			}
-		}
+	k.releasePods(experiment)
+	activeExperiments.WithLabelValues(experiment.Request.Action).Dec()
+	deleteExperimentMetrics(experiment)
+
+	stats := experiment.stats()
+	if stats.Matched == 0 {
+		log.Warningf("experiment %s is canceled without matching any DNS request, check its patterns and pods", req.Name)
 	}
 
 	shouldDeleteNs := make([]string, 0, 1)
@@ -142,5 +592,115 @@ func (k Kubernetes) CancelDNSChaos(ctx context.Context, req *pb.CancelDNSChaosRe
 
 	return &pb.DNSChaosResponse{
 		Result: true,
+		Msg:    fmt.Sprintf("experiment %s is canceled after injecting chaos into %d of %d matched DNS requests", req.Name, stats.Injected, stats.Matched),
+		Stats:  stats,
 	}, nil
 }
+
+// ListDNSChaos returns all the experiments, sorted by name
+func (k *Kubernetes) ListDNSChaos(ctx context.Context, req *pb.ListDNSChaosRequest) (*pb.ListDNSChaosResponse, error) {
+	k.RLock()
+	defer k.RUnlock()
+
+	names := make([]string, 0, len(k.chaosMap))
+	for name := range k.chaosMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := &pb.ListDNSChaosResponse{
+		Experiments: make([]*pb.DNSChaosInfo, 0, len(names)),
+		Pause:       k.pauseStatus(),
+	}
+	for _, name := range names {
+		resp.Experiments = append(resp.Experiments, k.experimentInfo(k.chaosMap[name]))
+	}
+
+	return resp, nil
+}
+
+// GetDNSChaos returns the experiment with the name in request
+func (k *Kubernetes) GetDNSChaos(ctx context.Context, req *pb.GetDNSChaosRequest) (*pb.DNSChaosInfo, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	k.RLock()
+	defer k.RUnlock()
+
+	experiment, ok := k.chaosMap[req.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "experiment %s not found", req.Name)
+	}
+
+	return k.experimentInfo(experiment), nil
+}
+
+// GetDNSChaosStats returns the statistics of the experiment with the name in request
+func (k *Kubernetes) GetDNSChaosStats(ctx context.Context, req *pb.GetDNSChaosStatsRequest) (*pb.DNSChaosStats, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	k.RLock()
+	defer k.RUnlock()
+
+	experiment, ok := k.chaosMap[req.Name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "experiment %s not found", req.Name)
+	}
+
+	return experiment.stats(), nil
+}
+
+// experimentInfo builds the information of an experiment, the caller should hold the lock
+func (k *Kubernetes) experimentInfo(experiment *Experiment) *pb.DNSChaosInfo {
+	createTime, err := ptypes.TimestampProto(experiment.CreateTime)
+	if err != nil {
+		log.Warningf("invalid create time of experiment %s: %v", experiment.Request.Name, err)
+	}
+
+	info := &pb.DNSChaosInfo{
+		Definition: experiment.Request,
+		Pods:       make([]*pb.PodStatus, 0, len(experiment.Request.Pods)),
+		CreateTime: createTime,
+		Hits:       experiment.HitCount(),
+		Generation: experiment.Generation,
+		DryRunHits: experiment.DryRunHitCount(),
+	}
+
+	for _, pod := range experiment.Request.Pods {
+		podStatus := &pb.PodStatus{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+		}
+		if podInfo, ok := k.podMap[pod.Namespace][pod.Name]; ok && podInfo.Experiment == experiment {
+			podStatus.Ip = podInfo.IP
+		}
+		info.Pods = append(info.Pods, podStatus)
+	}
+
+	return info
+}
+
+// WatchDNSChaosEvents streams the events of the DNS requests which chaos is injected into
+func (k *Kubernetes) WatchDNSChaosEvents(req *pb.WatchDNSChaosEventsRequest, stream pb.DNS_WatchDNSChaosEventsServer) error {
+	log.Infof("receive WatchDNSChaosEvents request %v", req)
+
+	subscriber := k.events.subscribe(req.Experiments)
+	defer k.events.unsubscribe(subscriber)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-subscriber.ch:
+			// the event is shared by all the subscribers, copy it before setting the dropped count
+			e := *event
+			e.Dropped = subscriber.takeDropped()
+			if err := stream.Send(&e); err != nil {
+				return err
+			}
+		}
+	}
+}
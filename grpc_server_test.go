@@ -0,0 +1,282 @@
+package kubernetes
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newGRPCTestKubernetes(pods ...*api.Pod) *Kubernetes {
+	objs := make([]runtime.Object, 0, len(pods))
+	for _, pod := range pods {
+		objs = append(objs, pod)
+	}
+
+	k := New([]string{"cluster.local."})
+	k.Client = fake.NewSimpleClientset(objs...).CoreV1()
+	return k
+}
+
+func TestListAndGetDNSChaos(t *testing.T) {
+	ctx := context.Background()
+	k := newGRPCTestKubernetes(testPod("testns", "busybox-0", "10.0.0.1"), testPod("testns", "busybox-1", "10.0.0.2"))
+
+	for _, req := range []*pb.SetDNSChaosRequest{
+		{Name: "b", Action: ActionError, Pods: []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}, {Namespace: "testns", Name: "busybox-1"}}},
+		// takes over busybox-1 from experiment b
+		{Name: "a", Action: ActionRandom, Patterns: []string{"google.com"}, Pods: []*pb.Pod{{Namespace: "testns", Name: "busybox-1"}}},
+	} {
+		if _, err := k.SetDNSChaos(ctx, req); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	k.ipPodMap["10.0.0.1"].Experiment.hit(k.ipPodMap["10.0.0.1"])
+
+	list, err := k.ListDNSChaos(ctx, &pb.ListDNSChaosRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list.Experiments) != 2 || list.Experiments[0].Definition.Name != "a" || list.Experiments[1].Definition.Name != "b" {
+		t.Fatalf("Expected experiments a and b, got %v", list.Experiments)
+	}
+
+	info, err := k.GetDNSChaos(ctx, &pb.GetDNSChaosRequest{Name: "b"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.Hits != 1 {
+		t.Errorf("Expected 1 hit, got %d", info.Hits)
+	}
+	if info.CreateTime == nil {
+		t.Errorf("Expected create time to be set")
+	}
+	expectedIPs := []string{"10.0.0.1", ""}
+	for i, pod := range info.Pods {
+		if pod.Ip != expectedIPs[i] {
+			t.Errorf("Expected IP %q for pod %s, got %q", expectedIPs[i], pod.Name, pod.Ip)
+		}
+	}
+
+	_, err = k.GetDNSChaos(ctx, &pb.GetDNSChaosRequest{Name: "c"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	if _, err := k.CancelDNSChaos(ctx, &pb.CancelDNSChaosRequest{Name: "b"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// the pod taken over by experiment a is kept
+	if podInfo := k.ipPodMap["10.0.0.2"]; podInfo == nil || podInfo.Action != ActionRandom {
+		t.Errorf("Expected random chaos on pod busybox-1, got %v", podInfo)
+	}
+	list, _ = k.ListDNSChaos(ctx, &pb.ListDNSChaosRequest{})
+	if len(list.Experiments) != 1 {
+		t.Errorf("Expected 1 experiment after cancel, got %d", len(list.Experiments))
+	}
+}
+
+func TestUpdateDNSChaos(t *testing.T) {
+	ctx := context.Background()
+	k := newGRPCTestKubernetes(testPod("testns", "busybox-0", "10.0.0.1"), testPod("testns", "busybox-1", "10.0.0.2"))
+
+	resp, err := k.SetDNSChaos(ctx, &pb.SetDNSChaosRequest{
+		Name:   "chaos",
+		Action: ActionError,
+		Pods:   []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Generation != 1 {
+		t.Fatalf("Expected generation 1, got %d", resp.Generation)
+	}
+	experiment := k.chaosMap["chaos"]
+	experiment.hit(k.podMap["testns"]["busybox-0"])
+
+	update := &pb.UpdateDNSChaosRequest{
+		Chaos: &pb.SetDNSChaosRequest{
+			Name:   "chaos",
+			Action: ActionRandom,
+			Pods:   []*pb.Pod{{Namespace: "testns", Name: "busybox-1"}},
+		},
+		Generation: 1,
+	}
+	resp, err = k.UpdateDNSChaos(ctx, update)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Generation != 2 {
+		t.Errorf("Expected generation 2, got %d", resp.Generation)
+	}
+	if k.chaosMap["chaos"] != experiment || experiment.HitCount() != 1 {
+		t.Errorf("Expected the experiment and its hits to be kept")
+	}
+	if podInfo := k.ipPodMap["10.0.0.1"]; podInfo != nil {
+		t.Errorf("Expected chaos on pod busybox-0 to be removed, got %v", podInfo)
+	}
+	if podInfo := k.ipPodMap["10.0.0.2"]; podInfo == nil || podInfo.Action != ActionRandom {
+		t.Errorf("Expected random chaos on pod busybox-1, got %v", podInfo)
+	}
+
+	// a stale generation is rejected
+	_, err = k.UpdateDNSChaos(ctx, update)
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected Aborted, got %v", err)
+	}
+
+	// a failed update leaves the experiment as it was
+	_, err = k.UpdateDNSChaos(ctx, &pb.UpdateDNSChaosRequest{
+		Chaos: &pb.SetDNSChaosRequest{
+			Name:   "chaos",
+			Action: ActionError,
+			Pods:   []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}, {Namespace: "testns", Name: "missing"}},
+		},
+	})
+	if err == nil {
+		t.Fatalf("Expected error, got none")
+	}
+	if experiment.Generation != 2 || k.ipPodMap["10.0.0.1"] != nil || k.ipPodMap["10.0.0.2"] == nil {
+		t.Errorf("Expected experiment to be unchanged after a failed update")
+	}
+
+	// replacing the experiment keeps its generation increasing, so the generations seen before are stale
+	resp, err = k.SetDNSChaos(ctx, &pb.SetDNSChaosRequest{
+		Name:   "chaos",
+		Action: ActionError,
+		Pods:   []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Generation != 3 {
+		t.Errorf("Expected generation 3, got %d", resp.Generation)
+	}
+	for _, generation := range []int64{1, 2} {
+		update.Generation = generation
+		_, err = k.UpdateDNSChaos(ctx, update)
+		if status.Code(err) != codes.Aborted {
+			t.Errorf("Expected Aborted for generation %d, got %v", generation, err)
+		}
+	}
+	if podInfo := k.ipPodMap["10.0.0.1"]; podInfo == nil || podInfo.Action != ActionError {
+		t.Errorf("Expected error chaos on pod busybox-0 to be kept, got %v", podInfo)
+	}
+
+	_, err = k.UpdateDNSChaos(ctx, &pb.UpdateDNSChaosRequest{Chaos: &pb.SetDNSChaosRequest{
+		Name:   "missing",
+		Action: ActionError,
+		Pods:   []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}},
+	}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestSetDNSChaosValidation(t *testing.T) {
+	ctx := context.Background()
+	pending := testPod("testns", "pending", "")
+	k := newGRPCTestKubernetes(testPod("testns", "busybox-0", "10.0.0.1"), pending)
+	pod := []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}}
+
+	tests := []struct {
+		req          *pb.SetDNSChaosRequest
+		expectedCode codes.Code
+	}{
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionRandom, RandomMode: RandomPerPod, Pods: pod}, codes.OK},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Pods: pod}, codes.OK},
+		{&pb.SetDNSChaosRequest{Action: ActionError, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: "delay", Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Scope: "cluster", Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionRandom, RandomMode: "stable", Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Protocol: "quic", Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Qtypes: []string{"AAAA", "BOGUS"}, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionMalformed, MalformedVariant: "header", Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionMutate, MutateModes: []string{MutateShift, "rotate"}, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionMutate, MutateModes: []string{MutateTTL}, MutateTtl: maxTTL + 1, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionPartial, DropFraction: 1.5, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionPartial, DropIps: []string{"172.0.0.2", "172.0.0"}, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionHotspot, HotspotIp: "endpoint-0", Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionMisroute, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionMisroute, Misroute: map[string]string{"orders.prod": "orders"}, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionTTL, Ttl: maxTTL + 1, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionCNAME, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionCNAME, CnameTarget: "bad..name", Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionCNAMEChain, CnameDepth: maxCNAMEDepth + 1, Pods: pod}, codes.InvalidArgument},
+		// an experiment without pods does nothing, but it is accepted
+		{&pb.SetDNSChaosRequest{Name: "empty", Action: ActionError}, codes.OK},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Pods: []*pb.Pod{{Name: "busybox-0"}}}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Pods: append(pod, pod[0])}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Patterns: []string{""}, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Patterns: []string{"google.com", "google.com"}, Pods: pod}, codes.InvalidArgument},
+		{&pb.SetDNSChaosRequest{Name: "b", Action: ActionError, Pods: []*pb.Pod{{Namespace: "testns", Name: "missing"}}}, codes.NotFound},
+		{&pb.SetDNSChaosRequest{Name: "b", Action: ActionError, Pods: []*pb.Pod{{Namespace: "testns", Name: "pending"}}}, codes.FailedPrecondition},
+	}
+
+	for i, tc := range tests {
+		resp, err := k.SetDNSChaos(ctx, tc.req)
+		if status.Code(err) != tc.expectedCode {
+			t.Errorf("Test %d: Expected %v, got %v", i, tc.expectedCode, err)
+		}
+		if err == nil && resp.Msg == "" {
+			t.Errorf("Test %d: Expected msg to be filled", i)
+		}
+	}
+
+	// a failed request leaves nothing behind, even if some of its pods are found
+	_, err := k.SetDNSChaos(ctx, &pb.SetDNSChaosRequest{
+		Name:   "b",
+		Action: ActionRandom,
+		Pods:   []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}, {Namespace: "testns", Name: "missing"}},
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+	if _, ok := k.chaosMap["b"]; ok {
+		t.Errorf("Expected experiment b not to be created")
+	}
+	if podInfo := k.ipPodMap["10.0.0.1"]; podInfo == nil || podInfo.Experiment.Name != "a" || podInfo.Action != ActionError {
+		t.Errorf("Expected busybox-0 to be kept in experiment a, got %+v", podInfo)
+	}
+
+	if _, err := k.CancelDNSChaos(ctx, &pb.CancelDNSChaosRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for canceling without name, got %v", err)
+	}
+	if _, err := k.GetDNSChaos(ctx, &pb.GetDNSChaosRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for getting without name, got %v", err)
+	}
+}
+
+func TestGRPCServerRestart(t *testing.T) {
+	k := New([]string{"cluster.local."})
+	k.grpcAddress = unixSocketPrefix + filepath.Join(t.TempDir(), "chaos.sock")
+
+	for i := 0; i < 2; i++ {
+		if err := k.CreateGRPCServer(); err != nil {
+			t.Fatalf("Test %d: Expected no error, got %v", i, err)
+		}
+
+		conn, err := grpc.Dial(k.grpcAddress, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(5*time.Second))
+		if err != nil {
+			t.Fatalf("Test %d: Expected no error, got %v", i, err)
+		}
+		if _, err := pb.NewDNSClient(conn).ListDNSChaos(context.Background(), &pb.ListDNSChaosRequest{}); err != nil {
+			t.Errorf("Test %d: Expected no error, got %v", i, err)
+		}
+		conn.Close()
+
+		// the address is released, so that the server can be started again
+		if err := k.StopGRPCServer(); err != nil {
+			t.Fatalf("Test %d: Expected no error, got %v", i, err)
+		}
+	}
+}
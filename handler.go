@@ -4,27 +4,74 @@ import (
 	"context"
 
 	"github.com/coredns/coredns/plugin"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
 	"github.com/coredns/coredns/request"
 
+	tap "github.com/dnstap/golang-dnstap"
 	"github.com/miekg/dns"
 )
 
 // ServeDNS implements the plugin.Handler interface.
-func (k Kubernetes) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *dns.Msg) (int, error) {
+func (k *Kubernetes) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *dns.Msg) (rcode int, err error) {
 	state := request.Request{W: w, Req: r}
 	sourceIP := state.IP()
 	log.Debugf("k8s ServeDNS, source IP: %s, state: %v", sourceIP, state)
 
-	chaosPod, err := k.getChaosPod(sourceIP)
-	if err != nil {
-		log.Infof("fail to get pod information from cluster, IP: %s, error: %v", sourceIP, err)
+	var chaosPod *PodInfo
+	if !isChaosLookup(ctx) {
+		chaosPod, err = k.getChaosPod(sourceIP)
+		if err != nil {
+			log.Debugf("fail to get pod information from cluster, IP: %s, error: %v", sourceIP, err)
+		}
 	}
 
 	records, extra, zone, err := k.getRecords(ctx, state)
 	log.Debugf("records: %v, err: %v", records, err)
 
-	if k.needChaos(chaosPod, records, state.QName()) {
-		return k.chaosDNS(ctx, w, r, state, chaosPod)
+	needChaos := k.needChaos(chaosPod, records, state)
+	if needChaos && chaosPod.truncateRetry(state) {
+		// the retry over TCP after the truncated answer is served as usual
+		needChaos = false
+	}
+	if chaosPod != nil {
+		recordChaosRequest(chaosPod, needChaos, needChaos && !chaosPod.DryRun && !k.isPaused())
+	}
+	if chaosPod != nil && k.tap != nil {
+		k.tap.sendMsg(state, chaosPod, tap.Message_CLIENT_QUERY, r)
+		tw := &tapWriter{ResponseWriter: w, tap: k.tap, state: state, podInfo: chaosPod}
+		defer func() { tw.finish(r, rcode) }()
+		w = tw
+	}
+
+	if needChaos {
+		chaosPod.Experiment.match()
+		decision := decisionInjected
+		switch {
+		case chaosPod.DryRun:
+			// only record it, the real answer is served below
+			decision = decisionDryRun
+			chaosPod.Experiment.dryRunHit()
+			k.audit.query(state, chaosPod, decision)
+			log.Debugf("dry run: experiment %s would inject %s into %s %s from pod %s/%s",
+				chaosPod.Experiment.Name, chaosPod.Action, state.Type(), state.Name(), chaosPod.Namespace, chaosPod.Name)
+		case k.isPaused():
+			// the real answer is served below
+			decision = decisionPaused
+			log.Debugf("chaos is paused, serve %s %s from pod %s/%s", state.Type(), state.Name(), chaosPod.Namespace, chaosPod.Name)
+		default:
+			chaosPod.Experiment.hit(chaosPod)
+			k.audit.query(state, chaosPod, decision)
+			if k.tap != nil {
+				k.tap.sendMsg(state, chaosPod, tap.Message_AUTH_RESPONSE, k.realResponse(ctx, state, zone, records, extra, err))
+			}
+			return k.injectChaos(ctx, w, r, state, chaosPod)
+		}
+		if k.events.hasSubscribers() {
+			// the real answer served below is recorded for the event
+			rw := dnstest.NewRecorder(w)
+			defer func() { k.events.publish(newChaosEvent(state, chaosPod, decision, rcode, rw.Msg)) }()
+			w = rw
+		}
 	}
 
 	if k.IsNameError(err) {
@@ -37,16 +84,16 @@ func (k Kubernetes) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *dns.M
 		}
 		if !k.APIConn.HasSynced() {
 			// If we haven't synchronized with the kubernetes cluster, return server failure
-			return plugin.BackendError(ctx, &k, zone, dns.RcodeServerFailure, state, nil /* err */, plugin.Options{})
+			return plugin.BackendError(ctx, k, zone, dns.RcodeServerFailure, state, nil /* err */, plugin.Options{})
 		}
-		return plugin.BackendError(ctx, &k, zone, dns.RcodeNameError, state, nil /* err */, plugin.Options{})
+		return plugin.BackendError(ctx, k, zone, dns.RcodeNameError, state, nil /* err */, plugin.Options{})
 	}
 	if err != nil {
 		return dns.RcodeServerFailure, err
 	}
 
 	if len(records) == 0 {
-		return plugin.BackendError(ctx, &k, zone, dns.RcodeSuccess, state, nil, plugin.Options{})
+		return plugin.BackendError(ctx, k, zone, dns.RcodeSuccess, state, nil, plugin.Options{})
 	}
 
 	m := new(dns.Msg)
@@ -59,8 +106,20 @@ func (k Kubernetes) ServeDNS(ctx context.Context, w dns.ResponseWriter, r *dns.M
 	return dns.RcodeSuccess, nil
 }
 
+// injectChaos serves the chaos answer, which is recorded for the events and dnstap if they are watched
+func (k *Kubernetes) injectChaos(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	if !k.events.hasSubscribers() && k.tap == nil {
+		return k.chaosDNS(ctx, w, r, state, podInfo)
+	}
+
+	rw := dnstest.NewRecorder(w)
+	rcode, err := k.chaosDNS(ctx, rw, r, state, podInfo)
+	k.events.publish(newChaosEvent(state, podInfo, decisionInjected, rcode, rw.Msg))
+	return rcode, err
+}
+
 // get records from cache
-func (k Kubernetes) getRecords(ctx context.Context, state request.Request) ([]dns.RR, []dns.RR, string, error) {
+func (k *Kubernetes) getRecords(ctx context.Context, state request.Request) ([]dns.RR, []dns.RR, string, error) {
 	qname := state.QName()
 	zone := plugin.Zones(k.Zones).Matches(qname)
 
@@ -77,24 +136,24 @@ func (k Kubernetes) getRecords(ctx context.Context, state request.Request) ([]dn
 	case dns.TypeAXFR, dns.TypeIXFR:
 		k.Transfer(ctx, state)
 	case dns.TypeA:
-		records, err = plugin.A(ctx, &k, zone, state, nil, plugin.Options{})
+		records, err = plugin.A(ctx, k, zone, state, nil, plugin.Options{})
 	case dns.TypeAAAA:
-		records, err = plugin.AAAA(ctx, &k, zone, state, nil, plugin.Options{})
+		records, err = plugin.AAAA(ctx, k, zone, state, nil, plugin.Options{})
 	case dns.TypeTXT:
-		records, err = plugin.TXT(ctx, &k, zone, state, nil, plugin.Options{})
+		records, err = plugin.TXT(ctx, k, zone, state, nil, plugin.Options{})
 	case dns.TypeCNAME:
-		records, err = plugin.CNAME(ctx, &k, zone, state, plugin.Options{})
+		records, err = plugin.CNAME(ctx, k, zone, state, plugin.Options{})
 	case dns.TypePTR:
-		records, err = plugin.PTR(ctx, &k, zone, state, plugin.Options{})
+		records, err = plugin.PTR(ctx, k, zone, state, plugin.Options{})
 	case dns.TypeMX:
-		records, extra, err = plugin.MX(ctx, &k, zone, state, plugin.Options{})
+		records, extra, err = plugin.MX(ctx, k, zone, state, plugin.Options{})
 	case dns.TypeSRV:
-		records, extra, err = plugin.SRV(ctx, &k, zone, state, plugin.Options{})
+		records, extra, err = plugin.SRV(ctx, k, zone, state, plugin.Options{})
 	case dns.TypeSOA:
-		records, err = plugin.SOA(ctx, &k, zone, state, plugin.Options{})
+		records, err = plugin.SOA(ctx, k, zone, state, plugin.Options{})
 	case dns.TypeNS:
 		if state.Name() == zone {
-			records, extra, err = plugin.NS(ctx, &k, zone, state, plugin.Options{})
+			records, extra, err = plugin.NS(ctx, k, zone, state, plugin.Options{})
 			break
 		}
 		fallthrough
@@ -102,11 +161,11 @@ func (k Kubernetes) getRecords(ctx context.Context, state request.Request) ([]dn
 		// Do a fake A lookup, so we can distinguish between NODATA and NXDOMAIN
 		fake := state.NewWithQuestion(state.QName(), dns.TypeA)
 		fake.Zone = state.Zone
-		_, err = plugin.A(ctx, &k, zone, fake, nil, plugin.Options{})
+		_, err = plugin.A(ctx, k, zone, fake, nil, plugin.Options{})
 	}
 
 	return records, extra, zone, err
 }
 
 // Name implements the Handler interface.
-func (k Kubernetes) Name() string { return "k8s_dns_chaos" }
+func (k *Kubernetes) Name() string { return "k8s_dns_chaos" }
@@ -0,0 +1,199 @@
+package kubernetes
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// stashedStates keeps the chaos state of the instances being reloaded by the grpc listen address,
+// the new instance listening on the same address takes it over, so that the experiments survive
+// Corefile reloads. startingInstances keeps the new instances which took over the state on startup
+// until the reload succeeded or failed, a failed reload discards the new instance without shutting it
+// down, so the instance being reloaded rolls back its changes to the state.
+var (
+	stashedStatesLock sync.Mutex
+	stashedStates     = make(map[string]*chaosState)
+	startingInstances = make(map[string]*Kubernetes)
+)
+
+// chaosHandover records the changes of startChaosState to the state taken over, so that they can be
+// rolled back if the reload fails after it
+type chaosHandover struct {
+	state *chaosState
+	// removed are the pods configured in the old Corefile, added are the ones configured in the new one
+	removed []*PodInfo
+	added   []*PodInfo
+
+	paused      bool
+	pauseReason string
+	pauseTime   time.Time
+}
+
+// stashChaosState is called before Corefile is reloaded.
+func (k *Kubernetes) stashChaosState() {
+	stashedStatesLock.Lock()
+	defer stashedStatesLock.Unlock()
+
+	stashedStates[k.grpcListenAddress()] = k.chaosState
+}
+
+// dropStashedChaosState removes the stashed state if it isn't taken over, and forgets the instance which
+// took it over, it is called when the reload failed or the instance is shut down after a reload succeeded.
+func (k *Kubernetes) dropStashedChaosState() {
+	stashedStatesLock.Lock()
+	defer stashedStatesLock.Unlock()
+
+	address := k.grpcListenAddress()
+	if stashedStates[address] == k.chaosState {
+		delete(stashedStates, address)
+	}
+	if n := startingInstances[address]; n != nil && n.handover.state == k.chaosState {
+		delete(startingInstances, address)
+	}
+}
+
+// rollBackChaosState undoes the changes of the instance which took over the state on startup, it is
+// called when the reload failed after that instance started. The things the discarded instance started
+// are stopped, so that the grpc server of this instance can listen on the address again.
+func (k *Kubernetes) rollBackChaosState() {
+	address := k.grpcListenAddress()
+	stashedStatesLock.Lock()
+	n := startingInstances[address]
+	if n == nil || n.handover.state != k.chaosState {
+		stashedStatesLock.Unlock()
+		return
+	}
+	delete(startingInstances, address)
+	stashedStatesLock.Unlock()
+
+	n.stopDiscarded()
+
+	k.Lock()
+	defer k.Unlock()
+
+	h := n.handover
+	for _, podInfo := range h.added {
+		if k.podMap[podInfo.Namespace][podInfo.Name] == podInfo {
+			delete(k.podMap[podInfo.Namespace], podInfo.Name)
+			if len(k.podMap[podInfo.Namespace]) == 0 {
+				delete(k.podMap, podInfo.Namespace)
+			}
+		}
+		if k.ipPodMap[podInfo.IP] == podInfo {
+			delete(k.ipPodMap, podInfo.IP)
+		}
+	}
+	for _, podInfo := range h.removed {
+		if _, ok := k.podMap[podInfo.Namespace]; !ok {
+			k.podMap[podInfo.Namespace] = make(map[string]*PodInfo)
+		}
+		if _, ok := k.podMap[podInfo.Namespace][podInfo.Name]; ok {
+			// the pod is taken over by an experiment in the meantime
+			continue
+		}
+		k.podMap[podInfo.Namespace][podInfo.Name] = podInfo
+		if _, ok := k.ipPodMap[podInfo.IP]; podInfo.IP != "" && !ok {
+			k.ipPodMap[podInfo.IP] = podInfo
+		}
+	}
+	k.setPaused(h.paused, h.pauseReason, h.pauseTime)
+
+	log.Warningf("the reload failed, roll back the chaos configured in Corefile")
+}
+
+// stopDiscarded stops what the instance started on startup, it is called for the instance discarded by
+// a failed reload, which isn't shut down.
+func (k *Kubernetes) stopDiscarded() {
+	if err := k.StopGRPCServer(); err != nil {
+		log.Warningf("fail to stop the grpc server of the discarded instance: %v", err)
+	}
+	if k.crdWatcher != nil {
+		k.crdWatcher.Stop()
+	}
+	if k.APIConn != nil {
+		k.APIConn.Stop()
+	}
+	if k.audit != nil {
+		k.audit.close()
+	}
+	if k.tap != nil {
+		k.tap.close()
+	}
+}
+
+// takeOverChaosState finds the chaos state of the instance being reloaded during the setup. The state
+// is still used by the old instance, which keeps serving with it if the reload fails, so it is only
+// changed by startChaosState after the setup succeeded.
+func (k *Kubernetes) takeOverChaosState() {
+	stashedStatesLock.Lock()
+	defer stashedStatesLock.Unlock()
+
+	k.takenOverState = stashedStates[k.grpcListenAddress()]
+}
+
+// startChaosState takes over the experiments of the instance being reloaded on startup. The chaos
+// configured in the old Corefile is replaced by the chaos configured in the new one, the changes are
+// recorded to be rolled back by the instance being reloaded if the reload fails after it.
+func (k *Kubernetes) startChaosState() {
+	state := k.takenOverState
+	if state == nil {
+		return
+	}
+	k.takenOverState = nil
+
+	stashedStatesLock.Lock()
+	if stashedStates[k.grpcListenAddress()] != state {
+		// the state is dropped or taken over by another instance in the meantime
+		stashedStatesLock.Unlock()
+		return
+	}
+	delete(stashedStates, k.grpcListenAddress())
+	h := &chaosHandover{state: state}
+	k.handover = h
+	startingInstances[k.grpcListenAddress()] = k
+	stashedStatesLock.Unlock()
+
+	// the old instance may still be serving with the state
+	state.Lock()
+	defer state.Unlock()
+
+	h.paused = atomic.LoadInt32(&state.paused) == 1
+	h.pauseReason, h.pauseTime = state.pauseReason, state.pauseTime
+
+	for namespace, pods := range state.podMap {
+		for name, podInfo := range pods {
+			if podInfo.Experiment == nil {
+				delete(pods, name)
+				if state.ipPodMap[podInfo.IP] == podInfo {
+					delete(state.ipPodMap, podInfo.IP)
+				}
+				h.removed = append(h.removed, podInfo)
+			}
+		}
+		if len(pods) == 0 {
+			delete(state.podMap, namespace)
+		}
+	}
+
+	// the pods configured in Corefile are resolved in InitKubeCache
+	for namespace, pods := range k.podMap {
+		for name, podInfo := range pods {
+			if _, ok := state.podMap[namespace]; !ok {
+				state.podMap[namespace] = make(map[string]*PodInfo)
+			}
+			if _, ok := state.podMap[namespace][name]; ok {
+				// the pod is taken over by an experiment
+				continue
+			}
+			state.podMap[namespace][name] = podInfo
+			h.added = append(h.added, podInfo)
+			if podInfo.IP != "" {
+				state.ipPodMap[podInfo.IP] = podInfo
+			}
+		}
+	}
+
+	log.Infof("take over %d experiments from the instance being reloaded", len(state.chaosMap))
+	k.chaosState = state
+}
@@ -0,0 +1,172 @@
+package kubernetes
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+)
+
+func TestTakeOverChaosState(t *testing.T) {
+	old := newGRPCTestKubernetes(testPod("testns", "busybox-0", "10.0.0.1"))
+	old.grpcPort = 19288
+	old.podMap["testns"] = map[string]*PodInfo{"corefile-0": {Namespace: "testns", Name: "corefile-0", IP: "10.0.0.9"}}
+	old.ipPodMap["10.0.0.9"] = old.podMap["testns"]["corefile-0"]
+	if _, err := old.SetDNSChaos(context.Background(), &pb.SetDNSChaosRequest{
+		Name:   "chaos",
+		Action: ActionError,
+		Pods:   []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}},
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	old.stashChaosState()
+
+	// an instance listening on another address doesn't take over the state
+	other := New([]string{"cluster.local."})
+	other.takeOverChaosState()
+	if len(other.chaosMap) != 0 {
+		t.Errorf("Expected no experiments to be taken over, got %v", other.chaosMap)
+	}
+
+	k := New([]string{"cluster.local."})
+	k.grpcAddress = ":19288"
+	k.podMap["testns"] = map[string]*PodInfo{"corefile-1": {Namespace: "testns", Name: "corefile-1", IP: "10.0.0.8"}}
+	k.takeOverChaosState()
+	if k.chaosState == old.chaosState {
+		t.Fatalf("Expected the chaos state not to be taken over before startup")
+	}
+	k.startChaosState()
+
+	if k.chaosState != old.chaosState {
+		t.Fatalf("Expected the chaos state to be taken over")
+	}
+	if _, ok := k.chaosMap["chaos"]; !ok {
+		t.Errorf("Expected experiment to be taken over")
+	}
+	if k.ipPodMap["10.0.0.1"] == nil {
+		t.Errorf("Expected the chaos of pod busybox-0 to be kept")
+	}
+	if _, ok := k.podMap["testns"]["corefile-0"]; ok || k.ipPodMap["10.0.0.9"] != nil {
+		t.Errorf("Expected the chaos configured in the old Corefile to be removed")
+	}
+	if _, ok := k.podMap["testns"]["corefile-1"]; !ok || k.ipPodMap["10.0.0.8"] == nil {
+		t.Errorf("Expected the chaos configured in the new Corefile to be added")
+	}
+
+	// the state can only be taken over once
+	old.dropStashedChaosState()
+	if len(stashedStates) != 0 {
+		t.Errorf("Expected no stashed states, got %v", stashedStates)
+	}
+}
+
+func TestTakeOverChaosStateFailedReload(t *testing.T) {
+	old := newGRPCTestKubernetes(testPod("testns", "busybox-0", "10.0.0.1"))
+	old.grpcPort = 19289
+	old.podMap["testns"] = map[string]*PodInfo{"corefile-0": {Namespace: "testns", Name: "corefile-0", IP: "10.0.0.9"}}
+	old.ipPodMap["10.0.0.9"] = old.podMap["testns"]["corefile-0"]
+	if _, err := old.SetDNSChaos(context.Background(), &pb.SetDNSChaosRequest{
+		Name:   "chaos",
+		Action: ActionError,
+		Pods:   []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}},
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	old.stashChaosState()
+
+	// the setup of the new instance succeeds, but the reload fails before it starts
+	k := New([]string{"cluster.local."})
+	k.grpcPort = 19289
+	k.podMap["testns"] = map[string]*PodInfo{"corefile-1": {Namespace: "testns", Name: "corefile-1", IP: "10.0.0.8"}}
+	k.takeOverChaosState()
+	old.dropStashedChaosState()
+
+	if old.ipPodMap["10.0.0.1"] == nil {
+		t.Errorf("Expected the chaos of pod busybox-0 to be kept")
+	}
+	if _, ok := old.podMap["testns"]["corefile-0"]; !ok || old.ipPodMap["10.0.0.9"] == nil {
+		t.Errorf("Expected the chaos configured in the old Corefile to be kept")
+	}
+	if _, ok := old.podMap["testns"]["corefile-1"]; ok || old.ipPodMap["10.0.0.8"] != nil {
+		t.Errorf("Expected the chaos configured in the rejected Corefile not to be added")
+	}
+
+	// the dropped state can't be taken over any more
+	k.startChaosState()
+	if k.chaosState == old.chaosState {
+		t.Errorf("Expected the dropped chaos state not to be taken over")
+	}
+}
+
+func TestTakeOverChaosStateStartupFailed(t *testing.T) {
+	old := newGRPCTestKubernetes(testPod("testns", "busybox-0", "10.0.0.1"))
+	old.grpcPort = 19290
+	old.podMap["testns"] = map[string]*PodInfo{"corefile-0": {Namespace: "testns", Name: "corefile-0", IP: "10.0.0.9"}}
+	old.ipPodMap["10.0.0.9"] = old.podMap["testns"]["corefile-0"]
+	if _, err := old.SetDNSChaos(context.Background(), &pb.SetDNSChaosRequest{
+		Name:   "chaos",
+		Action: ActionError,
+		Pods:   []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}},
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := old.CreateGRPCServer(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer old.StopGRPCServer()
+
+	// OnRestart of the instance being reloaded
+	old.stashChaosState()
+	if err := old.StopGRPCServer(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// the new instance takes over the state and starts its grpc server, the kill switch of the new
+	// Corefile pauses chaos
+	killSwitchFile := filepath.Join(t.TempDir(), "kill_switch")
+	if err := ioutil.WriteFile(killSwitchFile, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	k := New([]string{"cluster.local."})
+	k.grpcPort = 19290
+	k.killSwitchFile = killSwitchFile
+	k.podMap["testns"] = map[string]*PodInfo{"corefile-1": {Namespace: "testns", Name: "corefile-1", IP: "10.0.0.8"}}
+	k.takeOverChaosState()
+	k.startChaosState()
+	if err := k.loadKillSwitch(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := k.CreateGRPCServer(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if k.chaosState != old.chaosState || !old.isPaused() {
+		t.Fatalf("Expected the chaos state to be taken over and paused")
+	}
+
+	// the startup fails after that, the new instance is discarded and the reload fails
+	if err := old.restartFailed(); err != nil {
+		t.Fatalf("Expected the grpc server to be started again, got %v", err)
+	}
+	if k.grpcServer != nil {
+		t.Errorf("Expected the grpc server of the discarded instance to be stopped")
+	}
+	if old.isPaused() {
+		t.Errorf("Expected the pause by the discarded instance to be rolled back")
+	}
+	if _, ok := old.chaosMap["chaos"]; !ok || old.ipPodMap["10.0.0.1"] == nil {
+		t.Errorf("Expected the experiment to be kept")
+	}
+	if _, ok := old.podMap["testns"]["corefile-0"]; !ok || old.ipPodMap["10.0.0.9"] == nil {
+		t.Errorf("Expected the chaos configured in the old Corefile to be restored")
+	}
+	if _, ok := old.podMap["testns"]["corefile-1"]; ok || old.ipPodMap["10.0.0.8"] != nil {
+		t.Errorf("Expected the chaos configured in the discarded Corefile to be removed")
+	}
+	if len(startingInstances) != 0 || len(stashedStates) != 0 {
+		t.Errorf("Expected nothing left of the reload, got %v and %v", startingInstances, stashedStates)
+	}
+}
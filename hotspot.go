@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/coredns/coredns/request"
+
+	"github.com/miekg/dns"
+)
+
+// hotspotChaos serves the real answer with all the addresses but one dropped, so that all the clients
+// are pinned to the same endpoint. The endpoint is HotspotIP if it is in the answer, otherwise the
+// first one. The SRV records of the other targets are dropped as well.
+func (k *Kubernetes) hotspotChaos(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	resp, err := k.Lookup(withChaosLookup(ctx), state, state.QName(), state.QType())
+	if err != nil {
+		return dns.RcodeServerFailure, err
+	}
+	if resp == nil {
+		// no plugin answered the lookup
+		return dns.RcodeServerFailure, nil
+	}
+
+	records := make([]dns.RR, 0, len(resp.Answer)+len(resp.Extra))
+	records = append(records, resp.Answer...)
+	records = append(records, resp.Extra...)
+	dropped := hotspotDropped(podInfo.HotspotIP, records)
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = resp.Authoritative
+	m.Rcode = resp.Rcode
+	m.Answer = dropRecords(resp.Answer, resp.Extra, dropped)
+	m.Ns = resp.Ns
+	m.Extra = dropRecords(resp.Extra, resp.Extra, dropped)
+
+	w.WriteMsg(m)
+	return dns.RcodeSuccess, nil
+}
+
+// hotspotDropped returns all the addresses in the records except the pinned one
+func hotspotDropped(hotspotIP string, records []dns.RR) map[string]struct{} {
+	pinned := ""
+	for _, rr := range records {
+		if ip := addressOf(rr); ip != nil {
+			if pinned == "" || ip.String() == hotspotIP {
+				pinned = ip.String()
+			}
+			if pinned == hotspotIP {
+				break
+			}
+		}
+	}
+
+	dropped := make(map[string]struct{})
+	for _, rr := range records {
+		if ip := addressOf(rr); ip != nil && ip.String() != pinned {
+			dropped[ip.String()] = struct{}{}
+		}
+	}
+	return dropped
+}
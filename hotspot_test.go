@@ -0,0 +1,56 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+)
+
+func TestHotspotChaos(t *testing.T) {
+	tests := []struct {
+		hotspotIP  string
+		expectedIP string
+	}{
+		// the first endpoint by default
+		{"", "172.0.0.2"},
+		{"172.0.0.4", "172.0.0.4"},
+		// an endpoint which is not in the answer
+		{"10.0.0.1", "172.0.0.2"},
+	}
+
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionHotspot, HotspotIp: tc.hotspotIP})
+		for j := 0; j < 3; j++ {
+			answers := serveMutated(t, k, "hdls1.testns.svc.cluster.local.")
+			if len(answers) != 1 {
+				t.Fatalf("Test %d: Expected 1 answer, got %v", i, answers)
+			}
+			if ip := answers[0].(*dns.A).A.String(); ip != tc.expectedIP {
+				t.Errorf("Test %d: Expected IP %s, got %s", i, tc.expectedIP, ip)
+			}
+		}
+	}
+}
+
+func TestHotspotChaosSRV(t *testing.T) {
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionHotspot, HotspotIp: "5678:abcd:0::2"})
+
+	m := new(dns.Msg)
+	m.SetQuestion("_http._tcp.hdls1.testns.svc.cluster.local.", dns.TypeSRV)
+	w := dnstest.NewRecorder(&test.ResponseWriter{})
+	k.ServeDNS(newChaosLookupContext(t, k), w, m)
+
+	if w.Msg == nil || w.Msg.Rcode != dns.RcodeSuccess {
+		t.Fatalf("Expected a successful answer, got %v", w.Msg)
+	}
+	if len(w.Msg.Answer) != 1 || w.Msg.Answer[0].(*dns.SRV).Target != "5678-abcd--2.hdls1.testns.svc.cluster.local." {
+		t.Errorf("Expected the SRV record of 5678-abcd--2, got %v", w.Msg.Answer)
+	}
+	if len(w.Msg.Extra) != 1 || w.Msg.Extra[0].(*dns.AAAA).AAAA.String() != "5678:abcd::2" {
+		t.Errorf("Expected the AAAA record of 5678:abcd::2, got %v", w.Msg.Extra)
+	}
+}
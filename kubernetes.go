@@ -8,10 +8,8 @@ import (
 	"math/rand"
 	"net"
 	"strings"
-	"sync"
 	"time"
 
-	"github.com/chaos-mesh/k8s_dns_chaos/pb"
 	"github.com/coredns/coredns/plugin"
 	"github.com/coredns/coredns/plugin/etcd/msg"
 	"github.com/coredns/coredns/plugin/kubernetes/object"
@@ -21,10 +19,13 @@ import (
 	"github.com/coredns/coredns/request"
 
 	"github.com/miekg/dns"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
 	api "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	typev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
@@ -61,13 +62,52 @@ type Kubernetes struct {
 
 	// grpc port is the port used for request chaos request
 	grpcPort int
-
-	sync.RWMutex
-	chaosMap map[string]*pb.SetDNSChaosRequest
-	// namespace -> pod_name -> pod info
-	podMap map[string]map[string]*PodInfo
-
-	ipPodMap map[string]*PodInfo
+	// grpcAddress is the address the grpc server listens on, in the form of [HOST]:PORT or
+	// unix://PATH, it overrides grpcPort
+	grpcAddress string
+	grpcServer  *grpc.Server
+	// grpcHealth reports the health of the grpc server, it is updated until grpcHealthStop is closed
+	grpcHealth     *health.Server
+	grpcHealthStop chan struct{}
+	// grpcHealthAddress is the address of a plaintext grpc server which only serves the health, for
+	// the readiness checks which can't pass the TLS and token of the grpc server
+	grpcHealthAddress string
+	grpcHealthServer  *grpc.Server
+	// grpcListeners are closed when the grpc servers are stopped, even if the servers haven't started
+	// serving on them yet, so that the addresses are released for the new instance on a reload
+	grpcListeners []net.Listener
+	// the grpc server uses TLS if grpcTLSCert is set, and requires client certificates
+	// signed by grpcTLSClientCA if it is set
+	grpcTLSCert     string
+	grpcTLSKey      string
+	grpcTLSClientCA string
+	// grpcTokenFile contains the bearer token required by the grpc server
+	grpcTokenFile string
+
+	// chaosCRD enables watching the DNSChaos custom resources in chaosCRDNamespace,
+	// all namespaces are watched if chaosCRDNamespace is empty
+	chaosCRD          bool
+	chaosCRDNamespace string
+	crdWatcher        *crdWatcher
+
+	// audit writes the changes of experiments and the chaos decisions of DNS requests, it is nil
+	// if audit_log isn't set
+	audit *auditLogger
+	// tap writes the DNS messages of the pods which chaos is applied to in dnstap format, it is nil
+	// if chaos_dnstap isn't set
+	tap *chaosTap
+
+	// killSwitchFile persists the pause of PauseAll, chaos is paused on startup if it exists
+	killSwitchFile string
+	// the limits of the blast radius of experiments, 0 means no limit
+	maxChaosPods             int
+	maxChaosNamespacePercent int
+
+	*chaosState
+	// takenOverState is the chaos state of the instance being reloaded, it is taken over on startup
+	takenOverState *chaosState
+	// handover records the changes to the state taken over on startup, until the reload succeeded
+	handover *chaosHandover
 }
 
 // New returns a initialized Kubernetes. It default interfaceAddrFunc to return 127.0.0.1. All other
@@ -78,9 +118,7 @@ func New(zones []string) *Kubernetes {
 	k.Namespaces = make(map[string]struct{})
 	k.podMode = podModeDisabled
 	k.ttl = defaultTTL
-	k.chaosMap = make(map[string]*pb.SetDNSChaosRequest)
-	k.podMap = make(map[string]map[string]*PodInfo)
-	k.ipPodMap = make(map[string]*PodInfo)
+	k.chaosState = newChaosState()
 	rand.Seed(time.Now().UnixNano())
 
 	return k
@@ -111,8 +149,6 @@ var (
 
 // Services implements the ServiceBackend interface.
 func (k *Kubernetes) Services(ctx context.Context, state request.Request, exact bool, opt plugin.Options) (svcs []msg.Service, err error) {
-	log.Infof("k8s services, source IP: %s \n", state.IP())
-
 	// We're looking again at types, which we've already done in ServeDNS, but there are some types k8s just can't answer.
 	switch state.QType() {
 
@@ -184,7 +220,7 @@ func (k *Kubernetes) primaryZone() string { return k.Zones[k.primaryZoneIndex] }
 
 // Lookup implements the ServiceBackend interface.
 func (k *Kubernetes) Lookup(ctx context.Context, state request.Request, name string, typ uint16) (*dns.Msg, error) {
-	log.Infof("k8s lookup, source IP: %s \n", state.IP())
+	log.Debugf("k8s lookup, source IP: %s", state.IP())
 	return k.Upstream.Lookup(ctx, state, name, typ)
 }
 
@@ -278,10 +314,23 @@ func (k *Kubernetes) InitKubeCache(ctx context.Context) (err error) {
 	k.opts.endpointNameMode = k.endpointNameMode
 	k.APIConn = newdnsController(ctx, kubeClient, k.opts)
 
-	// get IP for chaos Pod
+	if k.chaosCRD {
+		dynamicClient, err := dynamic.NewForConfig(config)
+		if err != nil {
+			return fmt.Errorf("failed to create DNSChaos watcher: %q", err)
+		}
+		k.crdWatcher = newCRDWatcher(k, dynamicClient, k.chaosCRDNamespace)
+	}
+
+	// get IP for chaos Pod configured in Corefile
+	k.Lock()
+	defer k.Unlock()
 	for _, pods := range k.podMap {
 		for name := range pods {
 			podInfo := pods[name]
+			if podInfo.Experiment != nil {
+				continue
+			}
 			pod, err := k.getPodFromCluster(podInfo.Namespace, podInfo.Name)
 			if err != nil {
 				return err
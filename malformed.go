@@ -0,0 +1,136 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/coredns/coredns/plugin/pkg/dnsutil"
+	"github.com/coredns/coredns/request"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// MalformedID means the answer has a wrong transaction ID
+	MalformedID = "id"
+	// MalformedQuestion means the question section of the answer doesn't match the query
+	MalformedQuestion = "question"
+	// MalformedTruncated means the wire bytes of the answer are cut in the middle
+	MalformedTruncated = "truncated"
+	// MalformedPointer means the answer has a compression pointer which loops or points out of the message
+	MalformedPointer = "pointer"
+	// MalformedLabel means the answer has a label longer than 63 octets
+	MalformedLabel = "label"
+
+	// headerSize is the size of the header of a DNS message, the question starts after it
+	headerSize = 12
+)
+
+// malformedVariants are the supported variants of the malformed action
+var malformedVariants = []string{MalformedID, MalformedQuestion, MalformedTruncated, MalformedPointer, MalformedLabel}
+
+// isValidMalformedVariant returns whether the variant is supported, an empty variant means one of them
+// is chosen at random for every DNS request
+func isValidMalformedVariant(variant string) bool {
+	if variant == "" {
+		return true
+	}
+	for _, v := range malformedVariants {
+		if v == variant {
+			return true
+		}
+	}
+	return false
+}
+
+// malformedRand returns the source of the randomness of the malformed action. With a seed it is derived
+// from the seed and the question, so that the same query always gets the same broken answer.
+func malformedRand(podInfo *PodInfo, state request.Request) *rand.Rand {
+	if podInfo.MalformedSeed == 0 {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(strings.ToLower(state.QName())))
+	h.Write([]byte(state.Type()))
+	return rand.New(rand.NewSource(podInfo.MalformedSeed ^ int64(h.Sum64())))
+}
+
+// malformedChaos writes a deliberately broken DNS message as raw bytes, for testing the robustness of
+// the resolvers of the clients
+func (k *Kubernetes) malformedChaos(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	rnd := malformedRand(podInfo, state)
+
+	variant := podInfo.MalformedVariant
+	if variant == "" {
+		variant = malformedVariants[rnd.Intn(len(malformedVariants))]
+	}
+
+	buf, err := malformedMsg(r, state, variant, podInfo.chaosTTL(), rnd)
+	if err != nil {
+		return dns.RcodeServerFailure, err
+	}
+	log.Debugf("write malformed answer %s for %s %s", variant, state.Type(), state.Name())
+
+	w.Write(buf)
+	return dns.RcodeSuccess, nil
+}
+
+// malformedMsg builds the wire bytes of an answer broken in the way of the variant
+func malformedMsg(r *dns.Msg, state request.Request, variant string, ttl uint32, rnd *rand.Rand) ([]byte, error) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+	// the owner name of the answer is compressed to a pointer to the question
+	m.Compress = true
+	switch state.QType() {
+	case dns.TypeA:
+		ip := net.IPv4(byte(rnd.Intn(256)), byte(rnd.Intn(256)), byte(rnd.Intn(256)), byte(rnd.Intn(256)))
+		m.Answer = a(state.QName(), ttl, []net.IP{ip})
+	case dns.TypeAAAA:
+		m.Answer = aaaa(state.QName(), ttl, []net.IP{chaosIPv6})
+	}
+
+	if variant == MalformedQuestion {
+		m.Question[0].Name = dnsutil.Join(fmt.Sprintf("chaos-%d", rnd.Intn(10000)), state.QName())
+	}
+
+	buf, err := m.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	switch variant {
+	case MalformedID:
+		// never the ID of the query
+		binary.BigEndian.PutUint16(buf, r.Id^uint16(1+rnd.Intn(0xffff)))
+	case MalformedTruncated:
+		// the TC bit isn't set, the message is just cut. A message cut between records is accepted
+		// by some parsers, so it is cut shorter until it can't be parsed.
+		buf = buf[:headerSize+1+rnd.Intn(len(buf)-headerSize-1)]
+		for len(buf) > headerSize+1 {
+			if err := new(dns.Msg).Unpack(buf); err != nil {
+				break
+			}
+			buf = buf[:len(buf)-1]
+		}
+	case MalformedPointer:
+		// the question name becomes a pointer to itself or beyond the end of the message
+		offset := headerSize
+		if rnd.Intn(2) == 0 {
+			offset = len(buf) + rnd.Intn(0x3fff-len(buf))
+		}
+		binary.BigEndian.PutUint16(buf[headerSize:], 0xc000|uint16(offset))
+	case MalformedLabel:
+		// the label types between 64 and 191 are invalid
+		buf[headerSize] = byte(64 + rnd.Intn(128))
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,95 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+)
+
+// rawWriter records the raw bytes written to it
+type rawWriter struct {
+	test.ResponseWriter
+	buf []byte
+}
+
+func (w *rawWriter) Write(buf []byte) (int, error) {
+	w.buf = buf
+	return len(buf), nil
+}
+
+func TestMalformedChaos(t *testing.T) {
+	tests := []struct {
+		variant     string
+		expectValid bool
+	}{
+		{MalformedID, true},
+		{MalformedQuestion, true},
+		{MalformedTruncated, false},
+		{MalformedPointer, false},
+		{MalformedLabel, false},
+	}
+
+	for i, tc := range tests {
+		for _, qtype := range []uint16{dns.TypeA, dns.TypeSRV} {
+			k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionMalformed, MalformedVariant: tc.variant, MalformedSeed: 42})
+
+			serve := func() []byte {
+				m := new(dns.Msg)
+				m.SetQuestion("svc1.testns.svc.cluster.local.", qtype)
+				m.Id = 1234
+				w := &rawWriter{}
+				k.ServeDNS(context.TODO(), w, m)
+				return w.buf
+			}
+
+			buf := serve()
+			if len(buf) == 0 {
+				t.Fatalf("Test %d: Expected a raw answer, got none", i)
+			}
+			// the answer is reproducible with the seed
+			if again := serve(); !bytes.Equal(buf, again) {
+				t.Errorf("Test %d: Expected the same answer with the same seed, got %v and %v", i, buf, again)
+			}
+
+			resp := new(dns.Msg)
+			err := resp.Unpack(buf)
+			if (err == nil) != tc.expectValid {
+				t.Fatalf("Test %d: Expected valid message %v, got %v", i, tc.expectValid, err)
+			}
+			if err != nil {
+				continue
+			}
+
+			switch tc.variant {
+			case MalformedID:
+				if resp.Id == 1234 {
+					t.Errorf("Test %d: Expected a wrong transaction ID, got %d", i, resp.Id)
+				}
+			case MalformedQuestion:
+				if resp.Id != 1234 || resp.Question[0].Name == "svc1.testns.svc.cluster.local." {
+					t.Errorf("Test %d: Expected a wrong question, got %v", i, resp.Question[0])
+				}
+			}
+		}
+	}
+}
+
+func TestMalformedRandomVariant(t *testing.T) {
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionMalformed})
+
+	for i := 0; i < 20; i++ {
+		m := new(dns.Msg)
+		m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+		w := &rawWriter{}
+		k.ServeDNS(context.TODO(), w, m)
+
+		if len(w.buf) < headerSize {
+			t.Errorf("Test %d: Expected a raw answer, got %v", i, w.buf)
+		}
+	}
+}
@@ -1,18 +1,19 @@
 package kubernetes
 
 import (
+	"context"
 	"time"
 
 	"github.com/coredns/coredns/plugin"
 	"github.com/coredns/coredns/plugin/kubernetes/object"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 	api "k8s.io/api/core/v1"
 )
 
 var (
-	reg = prometheus.NewRegistry()
 	// DnsProgrammingLatency is defined as the time it took to program a DNS instance - from the time
 	// a service or pod has changed to the time the change was propagated and was available to be
 	// served by a DNS server.
@@ -24,7 +25,7 @@ var (
 	//   * cluster_ip
 	//   * headless_with_selector
 	//   * headless_without_selector
-	DnsProgrammingLatency = promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+	DnsProgrammingLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: plugin.Namespace,
 		Subsystem: pluginName,
 		Name:      "dns_programming_duration_seconds",
@@ -33,6 +34,69 @@ var (
 		Help:    "Histogram of the time (in seconds) it took to program a dns instance.",
 	}, []string{"service_kind"})
 
+	// The chaos metrics are labelled by the experiment, the action and the namespace of the client pod.
+	// The experiment label is empty for the chaos configured in Corefile.
+
+	// chaosMatched is the count of DNS requests matched by experiments, including the ones in dry run.
+	chaosMatched = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: plugin.Namespace,
+		Subsystem: pluginName,
+		Name:      "chaos_matched_total",
+		Help:      "Counter of DNS requests matched by chaos experiments.",
+	}, []string{"experiment", "action", "client_namespace"})
+	// chaosInjected is the count of DNS requests which chaos is injected into.
+	chaosInjected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: plugin.Namespace,
+		Subsystem: pluginName,
+		Name:      "chaos_injected_total",
+		Help:      "Counter of DNS requests which chaos is injected into.",
+	}, []string{"experiment", "action", "client_namespace"})
+	// chaosPassedThrough is the count of DNS requests from the pods of experiments which are served
+	// with the real answers, because they are not matched or the experiment is in dry run.
+	chaosPassedThrough = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: plugin.Namespace,
+		Subsystem: pluginName,
+		Name:      "chaos_passed_through_total",
+		Help:      "Counter of DNS requests from the pods of chaos experiments which are served with the real answers.",
+	}, []string{"experiment", "action", "client_namespace"})
+	// podResolutionFailures is the count of failures of refreshing the IP of the pods of experiments.
+	podResolutionFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: plugin.Namespace,
+		Subsystem: pluginName,
+		Name:      "pod_resolution_failures_total",
+		Help:      "Counter of failures of resolving the IP of the pods of chaos experiments.",
+	}, []string{"experiment", "client_namespace"})
+	// activeExperiments is the count of the experiments set through the grpc service.
+	activeExperiments = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: plugin.Namespace,
+		Subsystem: pluginName,
+		Name:      "active_experiments",
+		Help:      "The number of active chaos experiments.",
+	}, []string{"action"})
+	// chaosPaused is 1 when chaos is paused by PauseAll.
+	chaosPaused = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: plugin.Namespace,
+		Subsystem: pluginName,
+		Name:      "chaos_paused",
+		Help:      "Whether chaos injection is paused by the kill switch.",
+	})
+	// grpcRequests is the count of the requests of the grpc service by method and status code.
+	grpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: plugin.Namespace,
+		Subsystem: pluginName,
+		Name:      "grpc_requests_total",
+		Help:      "Counter of requests of the chaos grpc service.",
+	}, []string{"method", "code"})
+	// grpcRequestDuration is the time spent on the requests of the grpc service, the streams are
+	// observed when they end.
+	grpcRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: plugin.Namespace,
+		Subsystem: pluginName,
+		Name:      "grpc_request_duration_seconds",
+		Buckets:   plugin.TimeBuckets,
+		Help:      "Histogram of the time (in seconds) each request of the chaos grpc service took.",
+	}, []string{"method"})
+
 	// durationSinceFunc returns the duration elapsed since the given time.
 	// Added as a global variable to allow injection for testing.
 	durationSinceFunc = time.Since
@@ -72,3 +136,66 @@ func recordDNSProgrammingLatency(svcs []*object.Service, endpoints *api.Endpoint
 	DnsProgrammingLatency.WithLabelValues("headless_with_selector").
 		Observe(durationSinceFunc(lastChangeTriggerTime).Seconds())
 }
+
+// experimentLabel returns the experiment label of the pod, it is empty for the pods configured in Corefile
+func experimentLabel(podInfo *PodInfo) string {
+	if podInfo.Experiment == nil {
+		return ""
+	}
+	return podInfo.Experiment.Name
+}
+
+// metricSeries are the labels of the chaos metrics of an experiment besides its name, the action is
+// empty for the failures of resolving its pods
+type metricSeries struct {
+	action    string
+	namespace string
+}
+
+// recordChaosRequest records a DNS request from a pod which chaos is applied to
+func recordChaosRequest(podInfo *PodInfo, matched, injected bool) {
+	labels := []string{experimentLabel(podInfo), podInfo.Action, podInfo.Namespace}
+	podInfo.Experiment.addSeries(metricSeries{action: podInfo.Action, namespace: podInfo.Namespace})
+	if matched {
+		chaosMatched.WithLabelValues(labels...).Inc()
+	}
+	if injected {
+		chaosInjected.WithLabelValues(labels...).Inc()
+	} else {
+		chaosPassedThrough.WithLabelValues(labels...).Inc()
+	}
+}
+
+// deleteExperimentMetrics deletes the series of the chaos metrics of an experiment which is canceled
+// or replaced, so that the series of the experiments don't pile up
+func deleteExperimentMetrics(experiment *Experiment) {
+	for _, series := range experiment.metricSeries() {
+		if series.action == "" {
+			podResolutionFailures.DeleteLabelValues(experiment.Name, series.namespace)
+			continue
+		}
+		chaosMatched.DeleteLabelValues(experiment.Name, series.action, series.namespace)
+		chaosInjected.DeleteLabelValues(experiment.Name, series.action, series.namespace)
+		chaosPassedThrough.DeleteLabelValues(experiment.Name, series.action, series.namespace)
+	}
+}
+
+// recordGRPCRequest records the outcome and the duration of a request of the grpc service
+func recordGRPCRequest(method string, start time.Time, err error) {
+	grpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	grpcRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+func metricsUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	recordGRPCRequest(info.FullMethod, start, err)
+	return resp, err
+}
+
+func metricsStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	recordGRPCRequest(info.FullMethod, start, err)
+	return err
+}
@@ -6,9 +6,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
 	"github.com/coredns/coredns/plugin/kubernetes/object"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
 
+	"github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	api "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -60,31 +68,31 @@ func TestDnsProgrammingLatency(t *testing.T) {
 
 	controller.Stop()
 	expected := `
-        # HELP coredns_kubernetes_dns_programming_duration_seconds Histogram of the time (in seconds) it took to program a dns instance.
-        # TYPE coredns_kubernetes_dns_programming_duration_seconds histogram
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.001"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.002"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.004"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.008"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.016"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.032"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.064"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.128"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.256"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.512"} 0
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="1.024"} 1
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="2.048"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="4.096"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="8.192"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="16.384"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="32.768"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="65.536"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="131.072"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="262.144"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="524.288"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="+Inf"} 2
-        coredns_kubernetes_dns_programming_duration_seconds_sum{service_kind="headless_with_selector"} 3
-        coredns_kubernetes_dns_programming_duration_seconds_count{service_kind="headless_with_selector"} 2
+        # HELP coredns_k8s_dns_chaos_dns_programming_duration_seconds Histogram of the time (in seconds) it took to program a dns instance.
+        # TYPE coredns_k8s_dns_chaos_dns_programming_duration_seconds histogram
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.001"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.002"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.004"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.008"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.016"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.032"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.064"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.128"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.256"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="0.512"} 0
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="1.024"} 1
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="2.048"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="4.096"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="8.192"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="16.384"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="32.768"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="65.536"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="131.072"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="262.144"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="524.288"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_bucket{service_kind="headless_with_selector",le="+Inf"} 2
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_sum{service_kind="headless_with_selector"} 3
+        coredns_k8s_dns_chaos_dns_programming_duration_seconds_count{service_kind="headless_with_selector"} 2
 	`
 	if err := testutil.CollectAndCompare(DnsProgrammingLatency, strings.NewReader(expected)); err != nil {
 		t.Error(err)
@@ -135,3 +143,60 @@ func createService(t *testing.T, client kubernetes.Interface, controller dnsCont
 		t.Fatal(err)
 	}
 }
+
+func TestChaosMetrics(t *testing.T) {
+	chaosMatched.Reset()
+	chaosInjected.Reset()
+	chaosPassedThrough.Reset()
+	activeExperiments.Reset()
+
+	ctx := context.TODO()
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionError, Patterns: []string{"svc1.testns.*"}})
+	if v := testutil.ToFloat64(activeExperiments.WithLabelValues(ActionError)); v != 1 {
+		t.Errorf("Expected 1 active experiment, got %v", v)
+	}
+
+	for _, qname := range []string{"svc1.testns.svc.cluster.local.", "svc2.testns.svc.cluster.local.", "svc1.testns.svc.cluster.local."} {
+		m := new(dns.Msg)
+		m.SetQuestion(qname, dns.TypeA)
+		k.ServeDNS(ctx, dnstest.NewRecorder(&test.ResponseWriter{}), m)
+	}
+
+	labels := []string{"chaos", ActionError, "testns"}
+	if v := testutil.ToFloat64(chaosMatched.WithLabelValues(labels...)); v != 2 {
+		t.Errorf("Expected 2 matched requests, got %v", v)
+	}
+	if v := testutil.ToFloat64(chaosInjected.WithLabelValues(labels...)); v != 2 {
+		t.Errorf("Expected 2 injected requests, got %v", v)
+	}
+	if v := testutil.ToFloat64(chaosPassedThrough.WithLabelValues(labels...)); v != 1 {
+		t.Errorf("Expected 1 passed through request, got %v", v)
+	}
+
+	if _, err := k.CancelDNSChaos(ctx, &pb.CancelDNSChaosRequest{Name: "chaos"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if v := testutil.ToFloat64(activeExperiments.WithLabelValues(ActionError)); v != 0 {
+		t.Errorf("Expected no active experiment, got %v", v)
+	}
+	// the series of the canceled experiment are deleted
+	for _, c := range []*prometheus.CounterVec{chaosMatched, chaosInjected, chaosPassedThrough} {
+		if n := testutil.CollectAndCount(c); n != 0 {
+			t.Errorf("Expected no series after the experiment is canceled, got %d", n)
+		}
+	}
+}
+
+func TestGRPCMetrics(t *testing.T) {
+	grpcRequests.Reset()
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.DNS/GetDNSChaos"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "experiment not found")
+	}
+	metricsUnaryInterceptor(context.TODO(), nil, info, handler)
+
+	if v := testutil.ToFloat64(grpcRequests.WithLabelValues("/pb.DNS/GetDNSChaos", codes.NotFound.String())); v != 1 {
+		t.Errorf("Expected 1 NotFound request, got %v", v)
+	}
+}
@@ -0,0 +1,135 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/coredns/coredns/plugin"
+	"github.com/coredns/coredns/plugin/kubernetes/object"
+	"github.com/coredns/coredns/request"
+
+	"github.com/miekg/dns"
+	api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// parseMisroute checks the source to target mapping of the misroute action, the services are in the
+// form of SERVICE.NAMESPACE. The returned mapping is lowercased.
+func parseMisroute(misroute map[string]string) (map[string]string, error) {
+	parsed := make(map[string]string, len(misroute))
+	for source, target := range misroute {
+		source, target = strings.ToLower(source), strings.ToLower(target)
+		for _, key := range []string{source, target} {
+			segs := strings.Split(key, ".")
+			if len(segs) != 2 || len(validation.IsDNS1123Label(segs[0])) != 0 || len(validation.IsDNS1123Label(segs[1])) != 0 {
+				return nil, fmt.Errorf("invalid service %q in misroute, expected SERVICE.NAMESPACE", key)
+			}
+		}
+		if source == target {
+			return nil, fmt.Errorf("service %q is misrouted to itself", source)
+		}
+		parsed[source] = target
+	}
+	return parsed, nil
+}
+
+// misrouteChaos answers the A and AAAA queries of a source service with the records of its target
+// service in the Misroute of the pod, as if DNS answers with the wrong service. The records of the target
+// are looked up in the indexes for every DNS request, so they follow the changes of the cluster. The
+// other DNS requests are served with the real answers.
+func (k *Kubernetes) misrouteChaos(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	state.Zone = plugin.Zones(k.Zones).Matches(state.Name())
+	target, ok := misrouteTarget(state, podInfo)
+	if !ok || (state.QType() != dns.TypeA && state.QType() != dns.TypeAAAA) {
+		resp, err := k.Lookup(withChaosLookup(ctx), state, state.QName(), state.QType())
+		if err != nil {
+			return dns.RcodeServerFailure, err
+		}
+		if resp == nil {
+			// no plugin answered the lookup
+			return dns.RcodeServerFailure, nil
+		}
+
+		m := new(dns.Msg)
+		m.SetReply(r)
+		m.Authoritative = resp.Authoritative
+		m.Rcode = resp.Rcode
+		m.Answer = resp.Answer
+		m.Ns = resp.Ns
+		m.Extra = resp.Extra
+
+		w.WriteMsg(m)
+		return dns.RcodeSuccess, nil
+	}
+
+	svcs := k.APIConn.SvcIndex(target)
+	if len(svcs) == 0 {
+		// the target doesn't exist, neither does the source from the view of the client
+		return plugin.BackendError(ctx, k, state.Zone, dns.RcodeNameError, state, nil, plugin.Options{})
+	}
+
+	var ips []net.IP
+	for _, ip := range k.serviceIPs(target, svcs) {
+		if (ip.To4() != nil) == (state.QType() == dns.TypeA) {
+			ips = append(ips, ip)
+		}
+	}
+	if len(ips) == 0 {
+		return plugin.BackendError(ctx, k, state.Zone, dns.RcodeSuccess, state, nil, plugin.Options{})
+	}
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+	if state.QType() == dns.TypeA {
+		m.Answer = a(state.QName(), k.ttl, ips)
+	} else {
+		m.Answer = aaaa(state.QName(), k.ttl, ips)
+	}
+
+	w.WriteMsg(m)
+	return dns.RcodeSuccess, nil
+}
+
+// misrouteTarget returns the target service of the name of the DNS request, the name must be a service
+// in the zone of the request
+func misrouteTarget(state request.Request, podInfo *PodInfo) (string, bool) {
+	if state.Zone == "" {
+		return "", false
+	}
+
+	r, err := parseRequest(state.Name(), state.Zone)
+	if err != nil || r.podOrSvc != Svc || r.endpoint != "" || r.port != "*" || r.protocol != "*" {
+		return "", false
+	}
+
+	target, ok := podInfo.Misroute[strings.ToLower(r.service+"."+r.namespace)]
+	return target, ok
+}
+
+// serviceIPs returns the cluster IPs of the services, or the addresses of the endpoints of the headless
+// services
+func (k *Kubernetes) serviceIPs(key string, svcs []*object.Service) []net.IP {
+	var ips []net.IP
+	for _, svc := range svcs {
+		if svc.ClusterIP != api.ClusterIPNone {
+			if ip := net.ParseIP(svc.ClusterIP); ip != nil {
+				ips = append(ips, ip)
+			}
+			continue
+		}
+
+		for _, ep := range k.APIConn.EpIndex(key) {
+			for _, eps := range ep.Subsets {
+				for _, addr := range eps.Addresses {
+					if ip := net.ParseIP(addr.IP); ip != nil {
+						ips = append(ips, ip)
+					}
+				}
+			}
+		}
+	}
+	return ips
+}
@@ -0,0 +1,64 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+)
+
+func TestMisrouteChaos(t *testing.T) {
+	misroute := map[string]string{
+		"svc1.testns":     "hdls1.testns",
+		"HDLS1.testns":    "svc1.unexposedns",
+		"svc6.testns":     "svc1.testns",
+		"svcempty.testns": "missing.testns",
+	}
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionMisroute, Misroute: misroute})
+
+	tests := []struct {
+		qname         string
+		qtype         uint16
+		expectedRcode int
+		expectedIPs   []string
+	}{
+		// the endpoints of the headless target
+		{"svc1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess, []string{"172.0.0.2", "172.0.0.3", "172.0.0.4", "172.0.0.5"}},
+		{"svc1.testns.svc.cluster.local.", dns.TypeAAAA, dns.RcodeSuccess, []string{"5678:abcd::1", "5678:abcd::2"}},
+		{"hdls1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess, []string{"10.0.0.2"}},
+		{"svc6.testns.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess, []string{"10.0.0.1"}},
+		{"svc6.testns.svc.cluster.local.", dns.TypeAAAA, dns.RcodeSuccess, nil},
+		{"svcempty.testns.svc.cluster.local.", dns.TypeA, dns.RcodeNameError, nil},
+		// the names which are not misrouted are served with the real answers
+		{"svc1.unexposedns.svc.cluster.local.", dns.TypeA, dns.RcodeNameError, nil},
+		{"172-0-0-2.hdls1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess, []string{"172.0.0.2"}},
+	}
+
+	for i, tc := range tests {
+		m := new(dns.Msg)
+		m.SetQuestion(tc.qname, tc.qtype)
+		w := dnstest.NewRecorder(&test.ResponseWriter{})
+		k.ServeDNS(newChaosLookupContext(t, k), w, m)
+
+		if w.Msg == nil {
+			t.Fatalf("Test %d: Expected an answer, got none", i)
+		}
+		if w.Msg.Rcode != tc.expectedRcode {
+			t.Errorf("Test %d: Expected rcode %d, got %d", i, tc.expectedRcode, w.Msg.Rcode)
+		}
+		if len(w.Msg.Answer) != len(tc.expectedIPs) {
+			t.Fatalf("Test %d: Expected %d answers, got %v", i, len(tc.expectedIPs), w.Msg.Answer)
+		}
+		for j, rr := range w.Msg.Answer {
+			if ip := addressOf(rr); ip.String() != tc.expectedIPs[j] {
+				t.Errorf("Test %d: Expected IP %s of answer %d, got %s", i, tc.expectedIPs[j], j, ip)
+			}
+			if rr.Header().Name != tc.qname {
+				t.Errorf("Test %d: Expected the answer of %s, got %s", i, tc.qname, rr.Header().Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package kubernetes
+
+import (
+	"context"
+	"math/rand"
+	"net"
+
+	"github.com/coredns/coredns/plugin"
+	"github.com/coredns/coredns/request"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// MutateShift means every IP in the answer is shifted to a neighbouring address in the same subnet
+	MutateShift = "shift"
+	// MutateShuffle means the records in the answer are shuffled
+	MutateShuffle = "shuffle"
+	// MutateReverse means the records in the answer are reversed
+	MutateReverse = "reverse"
+	// MutateDuplicate means every record in the answer is duplicated
+	MutateDuplicate = "duplicate"
+	// MutateTTL means the TTLs of the records in the answer are rewritten
+	MutateTTL = "ttl"
+	// MutateSwap means the IPs in the answer are replaced by the cluster IP of another service
+	MutateSwap = "swap"
+)
+
+// mutateModes are the supported modes of the mutate action
+var mutateModes = []string{MutateShift, MutateShuffle, MutateReverse, MutateDuplicate, MutateTTL, MutateSwap}
+
+// defaultMutateModes are the modes of the mutate action if they are not set
+var defaultMutateModes = []string{MutateShift}
+
+// isValidMutateMode returns whether the mode is supported
+func isValidMutateMode(mode string) bool {
+	for _, m := range mutateModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// mutateChaos serves the real answer perturbed by the modes of the pod in order, so that it looks
+// plausible but is wrong. The real answer is resolved through the whole server, so the outer names
+// are mutated as well.
+func (k *Kubernetes) mutateChaos(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	resp, err := k.Lookup(withChaosLookup(ctx), state, state.QName(), state.QType())
+	if err != nil {
+		return dns.RcodeServerFailure, err
+	}
+	if resp == nil {
+		// no plugin answered the lookup
+		return dns.RcodeServerFailure, nil
+	}
+	if !plugin.ClientWrite(resp.Rcode) {
+		// the failure is written by the server
+		return resp.Rcode, nil
+	}
+
+	modes := podInfo.MutateModes
+	if len(modes) == 0 {
+		modes = defaultMutateModes
+	}
+
+	answers := make([]dns.RR, 0, len(resp.Answer))
+	for _, rr := range resp.Answer {
+		answers = append(answers, dns.Copy(rr))
+	}
+	for _, mode := range modes {
+		answers = k.mutate(mode, answers, podInfo)
+	}
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = resp.Authoritative
+	m.Rcode = resp.Rcode
+	m.Answer = answers
+	m.Ns = resp.Ns
+	m.Extra = resp.Extra
+
+	w.WriteMsg(m)
+	return resp.Rcode, nil
+}
+
+// mutate perturbs the records in the way of the mode, the records can be modified in place
+func (k *Kubernetes) mutate(mode string, answers []dns.RR, podInfo *PodInfo) []dns.RR {
+	switch mode {
+	case MutateShift:
+		for _, rr := range answers {
+			switch rr := rr.(type) {
+			case *dns.A:
+				rr.A = shiftIP(rr.A)
+			case *dns.AAAA:
+				rr.AAAA = shiftIP(rr.AAAA)
+			}
+		}
+	case MutateShuffle:
+		rand.Shuffle(len(answers), func(i, j int) { answers[i], answers[j] = answers[j], answers[i] })
+	case MutateReverse:
+		for i, j := 0, len(answers)-1; i < j; i, j = i+1, j-1 {
+			answers[i], answers[j] = answers[j], answers[i]
+		}
+	case MutateDuplicate:
+		duplicated := make([]dns.RR, 0, 2*len(answers))
+		for _, rr := range answers {
+			duplicated = append(duplicated, rr, dns.Copy(rr))
+		}
+		answers = duplicated
+	case MutateTTL:
+		for _, rr := range answers {
+			rr.Header().Ttl = podInfo.MutateTTL
+		}
+	case MutateSwap:
+		for _, rr := range answers {
+			switch rr := rr.(type) {
+			case *dns.A:
+				if ip := k.otherServiceIP(rr.A); ip != nil {
+					rr.A = ip.To4()
+				}
+			case *dns.AAAA:
+				if ip := k.otherServiceIP(rr.AAAA); ip != nil {
+					rr.AAAA = ip
+				}
+			}
+		}
+	}
+
+	return answers
+}
+
+// shiftIP returns the neighbouring address of the IP in the same /24 or /120 subnet
+func shiftIP(ip net.IP) net.IP {
+	shifted := make(net.IP, len(ip))
+	copy(shifted, ip)
+
+	last := len(shifted) - 1
+	if shifted[last] < 254 {
+		shifted[last]++
+	} else {
+		shifted[last]--
+	}
+	return shifted
+}
+
+// otherServiceIP returns the cluster IP of a random service other than the one of the IP, in the same
+// address family. It returns nil if there is no such service.
+func (k *Kubernetes) otherServiceIP(ip net.IP) net.IP {
+	var candidates []net.IP
+	for _, svc := range k.APIConn.ServiceList() {
+		clusterIP := net.ParseIP(svc.ClusterIP)
+		// the headless services have no cluster IP
+		if clusterIP == nil || clusterIP.Equal(ip) || (clusterIP.To4() == nil) != (ip.To4() == nil) {
+			continue
+		}
+		candidates = append(candidates, clusterIP)
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[rand.Intn(len(candidates))]
+}
@@ -0,0 +1,122 @@
+package kubernetes
+
+import (
+	"net"
+	"sort"
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+)
+
+func TestMutateChaos(t *testing.T) {
+	tests := []struct {
+		modes       []string
+		qname       string
+		expectedIPs []string
+		expectedTTL uint32
+	}{
+		// the default mode is shift
+		{nil, "svc1.testns.svc.cluster.local.", []string{"10.0.0.2"}, 5},
+		{[]string{MutateReverse}, "hdls1.testns.svc.cluster.local.", []string{"172.0.0.5", "172.0.0.4", "172.0.0.3", "172.0.0.2"}, 5},
+		{[]string{MutateDuplicate}, "svc1.testns.svc.cluster.local.", []string{"10.0.0.1", "10.0.0.1"}, 5},
+		{[]string{MutateTTL}, "svc1.testns.svc.cluster.local.", []string{"10.0.0.1"}, 3600},
+		{[]string{MutateShift, MutateReverse, MutateTTL}, "hdls1.testns.svc.cluster.local.", []string{"172.0.0.6", "172.0.0.5", "172.0.0.4", "172.0.0.3"}, 3600},
+	}
+
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionMutate, MutateModes: tc.modes, MutateTtl: 3600})
+		answers := serveMutated(t, k, tc.qname)
+
+		if len(answers) != len(tc.expectedIPs) {
+			t.Fatalf("Test %d: Expected %d answers, got %v", i, len(tc.expectedIPs), answers)
+		}
+		for j, rr := range answers {
+			if ip := rr.(*dns.A).A.String(); ip != tc.expectedIPs[j] {
+				t.Errorf("Test %d: Expected IP %s of answer %d, got %s", i, tc.expectedIPs[j], j, ip)
+			}
+			if rr.Header().Ttl != tc.expectedTTL {
+				t.Errorf("Test %d: Expected TTL %d of answer %d, got %d", i, tc.expectedTTL, j, rr.Header().Ttl)
+			}
+		}
+	}
+}
+
+func TestMutateShuffleAndSwap(t *testing.T) {
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionMutate, MutateModes: []string{MutateShuffle}})
+	answers := serveMutated(t, k, "hdls1.testns.svc.cluster.local.")
+	ips := make([]string, 0, len(answers))
+	for _, rr := range answers {
+		ips = append(ips, rr.(*dns.A).A.String())
+	}
+	sort.Strings(ips)
+	if len(ips) != 4 || ips[0] != "172.0.0.2" || ips[3] != "172.0.0.5" {
+		t.Errorf("Expected the shuffled records of hdls1, got %v", ips)
+	}
+
+	k = newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionMutate, MutateModes: []string{MutateSwap}})
+	answers = serveMutated(t, k, "svc1.testns.svc.cluster.local.")
+	if len(answers) != 1 {
+		t.Fatalf("Expected 1 answer, got %v", answers)
+	}
+	ip := answers[0].(*dns.A).A
+	if ip.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("Expected the IP of svc1 to be swapped, got %s", ip)
+	}
+	found := false
+	for _, svc := range k.APIConn.ServiceList() {
+		if net.ParseIP(svc.ClusterIP).Equal(ip) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the cluster IP of another service, got %s", ip)
+	}
+}
+
+func TestMutateChaosNameError(t *testing.T) {
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionMutate})
+	m := new(dns.Msg)
+	m.SetQuestion("svc0.testns.svc.cluster.local.", dns.TypeA)
+	w := dnstest.NewRecorder(&test.ResponseWriter{})
+	rcode, _ := k.ServeDNS(newChaosLookupContext(t, k), w, m)
+
+	// the rcode of the real answer is kept
+	if rcode != dns.RcodeNameError || w.Msg == nil || w.Msg.Rcode != dns.RcodeNameError {
+		t.Errorf("Expected NXDOMAIN, got rcode %d and %v", rcode, w.Msg)
+	}
+}
+
+// serveMutated returns the answers of the A query of qname from the client of test.ResponseWriter
+func serveMutated(t *testing.T, k *Kubernetes, qname string) []dns.RR {
+	m := new(dns.Msg)
+	m.SetQuestion(qname, dns.TypeA)
+	w := dnstest.NewRecorder(&test.ResponseWriter{})
+	k.ServeDNS(newChaosLookupContext(t, k), w, m)
+
+	if w.Msg == nil || w.Msg.Rcode != dns.RcodeSuccess {
+		t.Fatalf("Expected a successful answer, got %v", w.Msg)
+	}
+	return w.Msg.Answer
+}
+
+func TestShiftIP(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected string
+	}{
+		{"10.0.0.1", "10.0.0.2"},
+		{"10.0.0.254", "10.0.0.253"},
+		{"10.0.0.255", "10.0.0.254"},
+		{"2001:db8::1", "2001:db8::2"},
+	}
+
+	for i, tc := range tests {
+		if shifted := shiftIP(net.ParseIP(tc.ip)); shifted.String() != tc.expected {
+			t.Errorf("Test %d: Expected %s, got %s", i, tc.expected, shifted)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package kubernetes
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"net"
+	"sort"
+
+	"github.com/coredns/coredns/request"
+
+	"github.com/miekg/dns"
+)
+
+// defaultDropFraction is the fraction of the addresses dropped by the partial action if nothing is set
+const defaultDropFraction = 0.5
+
+// partialChaos serves the real answer with some of the addresses dropped, as if the endpoints are
+// stale during a rolling update or a zone outage. The addresses in DropIPs are dropped, otherwise
+// DropCount or DropFraction of them are. The dropped addresses are chosen by a hash of the experiment
+// and the address, so that the answers are consistent during the experiment.
+func (k *Kubernetes) partialChaos(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	resp, err := k.Lookup(withChaosLookup(ctx), state, state.QName(), state.QType())
+	if err != nil {
+		return dns.RcodeServerFailure, err
+	}
+	if resp == nil {
+		// no plugin answered the lookup
+		return dns.RcodeServerFailure, nil
+	}
+
+	records := make([]dns.RR, 0, len(resp.Answer)+len(resp.Extra))
+	records = append(records, resp.Answer...)
+	records = append(records, resp.Extra...)
+	dropped := droppedAddresses(podInfo, records)
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = resp.Authoritative
+	m.Rcode = resp.Rcode
+	m.Answer = dropRecords(resp.Answer, resp.Extra, dropped)
+	m.Ns = resp.Ns
+	m.Extra = dropRecords(resp.Extra, resp.Extra, dropped)
+
+	w.WriteMsg(m)
+	return dns.RcodeSuccess, nil
+}
+
+// droppedAddresses chooses the addresses in the records to be dropped
+func droppedAddresses(podInfo *PodInfo, records []dns.RR) map[string]struct{} {
+	dropped := make(map[string]struct{})
+	if len(podInfo.DropIPs) != 0 {
+		for _, ip := range podInfo.DropIPs {
+			dropped[ip] = struct{}{}
+		}
+		return dropped
+	}
+
+	seen := make(map[string]struct{})
+	ips := make([]string, 0, len(records))
+	for _, rr := range records {
+		if ip := addressOf(rr); ip != nil {
+			if _, ok := seen[ip.String()]; !ok {
+				seen[ip.String()] = struct{}{}
+				ips = append(ips, ip.String())
+			}
+		}
+	}
+
+	count := podInfo.DropCount
+	if count == 0 {
+		fraction := podInfo.DropFraction
+		if fraction == 0 {
+			fraction = defaultDropFraction
+		}
+		// rounded down, so that a fraction below 1 never drops all the addresses, e.g. of a single
+		// address answer
+		count = int(math.Floor(fraction * float64(len(ips))))
+	}
+	if count > len(ips) {
+		count = len(ips)
+	}
+
+	hashes := make(map[string]uint64, len(ips))
+	for _, ip := range ips {
+		h := fnv.New64a()
+		h.Write([]byte(experimentLabel(podInfo)))
+		h.Write([]byte{0})
+		h.Write([]byte(ip))
+		hashes[ip] = h.Sum64()
+	}
+	sort.Slice(ips, func(i, j int) bool { return hashes[ips[i]] < hashes[ips[j]] })
+
+	for _, ip := range ips[:count] {
+		dropped[ip] = struct{}{}
+	}
+	return dropped
+}
+
+// dropRecords removes the address records of the dropped addresses, and the SRV records whose targets
+// only have dropped addresses in extra
+func dropRecords(records, extra []dns.RR, dropped map[string]struct{}) []dns.RR {
+	// target -> whether it still has an address
+	targets := make(map[string]bool)
+	for _, rr := range extra {
+		if ip := addressOf(rr); ip != nil {
+			name := dns.CanonicalName(rr.Header().Name)
+			_, ok := dropped[ip.String()]
+			targets[name] = targets[name] || !ok
+		}
+	}
+
+	kept := make([]dns.RR, 0, len(records))
+	for _, rr := range records {
+		if ip := addressOf(rr); ip != nil {
+			if _, ok := dropped[ip.String()]; ok {
+				continue
+			}
+		}
+		if srv, ok := rr.(*dns.SRV); ok {
+			if alive, ok := targets[dns.CanonicalName(srv.Target)]; ok && !alive {
+				continue
+			}
+		}
+		kept = append(kept, rr)
+	}
+	return kept
+}
+
+// addressOf returns the address of an A or AAAA record, or nil for the other records
+func addressOf(rr dns.RR) net.IP {
+	switch rr := rr.(type) {
+	case *dns.A:
+		return rr.A
+	case *dns.AAAA:
+		return rr.AAAA
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+)
+
+func TestPartialChaos(t *testing.T) {
+	tests := []struct {
+		req           *pb.SetDNSChaosRequest
+		qname         string
+		expectedCount int
+		droppedIPs    []string
+	}{
+		// half of the addresses are dropped by default
+		{&pb.SetDNSChaosRequest{}, "hdls1.testns.svc.cluster.local.", 2, nil},
+		{&pb.SetDNSChaosRequest{DropCount: 1}, "hdls1.testns.svc.cluster.local.", 3, nil},
+		{&pb.SetDNSChaosRequest{DropCount: 10}, "hdls1.testns.svc.cluster.local.", 0, nil},
+		{&pb.SetDNSChaosRequest{DropFraction: 0.75}, "hdls1.testns.svc.cluster.local.", 1, nil},
+		{&pb.SetDNSChaosRequest{DropFraction: 0.3}, "hdls1.testns.svc.cluster.local.", 3, nil},
+		// the count of the fraction is rounded down, a single address is kept
+		{&pb.SetDNSChaosRequest{}, "svc1.testns.svc.cluster.local.", 1, nil},
+		{&pb.SetDNSChaosRequest{DropFraction: 0.9}, "svc1.testns.svc.cluster.local.", 1, nil},
+		{&pb.SetDNSChaosRequest{DropIps: []string{"172.0.0.3", "172.0.0.5"}}, "hdls1.testns.svc.cluster.local.", 2, []string{"172.0.0.3", "172.0.0.5"}},
+		{&pb.SetDNSChaosRequest{DropIps: []string{"10.0.0.2"}}, "svc1.testns.svc.cluster.local.", 1, nil},
+	}
+
+	for i, tc := range tests {
+		tc.req.Action = ActionPartial
+		k := newChaosTestKubernetes(t, tc.req)
+		answers := serveMutated(t, k, tc.qname)
+
+		if len(answers) != tc.expectedCount {
+			t.Fatalf("Test %d: Expected %d answers, got %v", i, tc.expectedCount, answers)
+		}
+		for _, rr := range answers {
+			for _, ip := range tc.droppedIPs {
+				if rr.(*dns.A).A.String() == ip {
+					t.Errorf("Test %d: Expected %s to be dropped, got %v", i, ip, answers)
+				}
+			}
+		}
+
+		// the same addresses are dropped during the experiment
+		again := serveMutated(t, k, tc.qname)
+		for j := range answers {
+			if !dns.IsDuplicate(answers[j], again[j]) {
+				t.Errorf("Test %d: Expected the same answers, got %v and %v", i, answers, again)
+			}
+		}
+	}
+}
+
+func TestPartialChaosSRV(t *testing.T) {
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionPartial, DropIps: []string{"172.0.0.2", "5678:abcd:0::1", "172.0.0.4"}})
+
+	m := new(dns.Msg)
+	m.SetQuestion("_http._tcp.hdls1.testns.svc.cluster.local.", dns.TypeSRV)
+	w := dnstest.NewRecorder(&test.ResponseWriter{})
+	k.ServeDNS(newChaosLookupContext(t, k), w, m)
+
+	if w.Msg == nil || w.Msg.Rcode != dns.RcodeSuccess {
+		t.Fatalf("Expected a successful answer, got %v", w.Msg)
+	}
+
+	// dup-name still has 172.0.0.5
+	expectedTargets := map[string]bool{
+		"172-0-0-3.hdls1.testns.svc.cluster.local.":    true,
+		"5678-abcd--2.hdls1.testns.svc.cluster.local.": true,
+		"dup-name.hdls1.testns.svc.cluster.local.":     true,
+	}
+	if len(w.Msg.Answer) != len(expectedTargets) {
+		t.Fatalf("Expected %d SRV records, got %v", len(expectedTargets), w.Msg.Answer)
+	}
+	for _, rr := range w.Msg.Answer {
+		if target := rr.(*dns.SRV).Target; !expectedTargets[target] {
+			t.Errorf("Expected the SRV record of %s to be dropped", target)
+		}
+	}
+	if len(w.Msg.Extra) != 3 {
+		t.Errorf("Expected 3 extra records, got %v", w.Msg.Extra)
+	}
+}
@@ -1,5 +1,5 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
-// source: dns.proto
+// source: pb/dns.proto
 
 package pb
 
@@ -13,6 +13,10 @@ import (
 	context "golang.org/x/net/context"
 
 	grpc "google.golang.org/grpc"
+
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -29,27 +33,108 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type SetDNSChaosRequest struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Pods []*Pod `protobuf:"bytes,2,rep,name=pods,proto3" json:"pods,omitempty"`
-	// action means the chaos action, values can be "random" or "error"
-	//   "random": return random IP for DNS request
-	//   "error":  return error for DNS request
+	// action means the chaos action:
+	//   "random":      return random IP for DNS request
+	//   "error":       return error for DNS request
+	//   "cname":       return a CNAME to cname_target, which is resolved as usual
+	//   "cname_loop":  return a CNAME loop, name -> chaos-loop.name -> name
+	//   "cname_chain": return a chain of cname_depth CNAMEs, which ends at cname_target if it is set
+	//   "truncate":    return a truncated answer over UDP, so that the client retries over TCP
+	//   "malformed":   return a malformed answer in the way of malformed_variant
+	//   "mutate":      return the real answer perturbed in the ways of mutate_modes
+	//   "partial":     return the real answer with some of the endpoints dropped
+	//   "hotspot":     return the real answer pinned to a single endpoint
+	//   "misroute":    return the records of the target service in misroute for the source service
+	//   "ttl":         return the real answer with the TTLs rewritten to ttl
 	Action string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
-	// scope means the chaos scope, values can be "inner", "outer" or "all":
-	//   "inner": chaos only works on the inner host in Kubernetes cluster
-	//   "outer": chaos only works on the outer host of Kubernetes cluster
-	//   "all":   chaos works on all host
-	Scope                string   `protobuf:"bytes,4,opt,name=scope,proto3" json:"scope,omitempty"`
-	Selector             string   `protobuf:"bytes,5,opt,name=selector,proto3" json:"selector,omitempty"`
-	Patterns             []string `protobuf:"bytes,6,rep,name=patterns,proto3" json:"patterns,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// scope means the chaos scope, values can be "inner", "outer" or "all", it is combined with patterns:
+	//   "inner": chaos only works on the inner host in Kubernetes cluster, the names in the zones of the plugin
+	//   "outer": chaos only works on the outer host of Kubernetes cluster, the names out of the zones
+	//   "all":   chaos works on all host, the default value
+	Scope    string   `protobuf:"bytes,4,opt,name=scope,proto3" json:"scope,omitempty"`
+	Selector string   `protobuf:"bytes,5,opt,name=selector,proto3" json:"selector,omitempty"`
+	Patterns []string `protobuf:"bytes,6,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	// dry_run matches the DNS requests as usual but always serves the real answers, the requests
+	// which chaos would be injected into are counted and logged. An experiment in dry run can be
+	// promoted to a live one by UpdateDNSChaos with dry_run unset.
+	DryRun bool `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// random_mode means how the random action chooses the IP, values can be "query", "name" or "pod":
+	//   "query": return a new random IP for every DNS request, the default value
+	//   "name":  return the same random IP for a name during the experiment
+	//   "pod":   return the same random IP for a name and a client pod during the experiment
+	RandomMode string `protobuf:"bytes,8,opt,name=random_mode,json=randomMode,proto3" json:"random_mode,omitempty"`
+	// cname_target is the target of the "cname" action, and the end of the chain of the "cname_chain" action
+	CnameTarget string `protobuf:"bytes,9,opt,name=cname_target,json=cnameTarget,proto3" json:"cname_target,omitempty"`
+	// cname_depth is the count of CNAMEs of the "cname_chain" action, the default value is 8
+	CnameDepth int32 `protobuf:"varint,10,opt,name=cname_depth,json=cnameDepth,proto3" json:"cname_depth,omitempty"`
+	// truncate_partial keeps the first record of the real answer in the truncated answers of the
+	// "truncate" action, the answers are empty by default
+	TruncatePartial bool `protobuf:"varint,11,opt,name=truncate_partial,json=truncatePartial,proto3" json:"truncate_partial,omitempty"`
+	// truncate_tcp_fail fails the DNS requests over TCP of the "truncate" action, for the clients which
+	// can't retry over TCP. They are served with the real answers by default.
+	TruncateTcpFail bool `protobuf:"varint,12,opt,name=truncate_tcp_fail,json=truncateTcpFail,proto3" json:"truncate_tcp_fail,omitempty"`
+	// protocol restricts chaos to the DNS requests over "udp" or "tcp", chaos works on both if it is empty
+	Protocol string `protobuf:"bytes,13,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	// qtypes restricts chaos to the DNS requests of these query types, for example "AAAA" or "SRV",
+	// chaos works on all the query types if it is empty
+	Qtypes []string `protobuf:"bytes,14,rep,name=qtypes,proto3" json:"qtypes,omitempty"`
+	// malformed_variant means how the "malformed" action breaks the answer, values can be "id", "question",
+	// "truncated", "pointer" or "label", one of them is chosen at random for every DNS request if it is empty:
+	//   "id":        the transaction ID is wrong
+	//   "question":  the question section doesn't match the query
+	//   "truncated": the wire bytes are cut in the middle
+	//   "pointer":   a compression pointer loops or points beyond the end of the message
+	//   "label":     a label is longer than 63 octets
+	MalformedVariant string `protobuf:"bytes,15,opt,name=malformed_variant,json=malformedVariant,proto3" json:"malformed_variant,omitempty"`
+	// malformed_seed makes the "malformed" action reproducible, the same query always gets the same
+	// broken answer with the same seed. The answers are random if it is 0.
+	MalformedSeed int64 `protobuf:"varint,16,opt,name=malformed_seed,json=malformedSeed,proto3" json:"malformed_seed,omitempty"`
+	// mutate_modes are the ways the "mutate" action perturbs the real answer in order, values can be
+	// "shift", "shuffle", "reverse", "duplicate", "ttl" or "swap", the default value is ["shift"]:
+	//   "shift":     shift every IP to a neighbouring address in the same subnet
+	//   "shuffle":   shuffle the records
+	//   "reverse":   reverse the order of the records
+	//   "duplicate": duplicate every record
+	//   "ttl":       rewrite the TTLs of the records to mutate_ttl
+	//   "swap":      replace every IP with the cluster IP of another service
+	MutateModes []string `protobuf:"bytes,17,rep,name=mutate_modes,json=mutateModes,proto3" json:"mutate_modes,omitempty"`
+	// mutate_ttl is the TTL of the records rewritten by the "ttl" mode of the "mutate" action,
+	// it must not be greater than 2147483647
+	MutateTtl uint32 `protobuf:"varint,18,opt,name=mutate_ttl,json=mutateTtl,proto3" json:"mutate_ttl,omitempty"`
+	// drop_count is the count of the addresses the "partial" action drops from the real answer
+	DropCount int32 `protobuf:"varint,19,opt,name=drop_count,json=dropCount,proto3" json:"drop_count,omitempty"`
+	// drop_fraction is the fraction of the addresses the "partial" action drops from the real answer,
+	// it is used if drop_count is 0. Half of the addresses are dropped if neither is set. The count is
+	// rounded down, so that a fraction below 1 keeps at least one address.
+	DropFraction float64 `protobuf:"fixed64,20,opt,name=drop_fraction,json=dropFraction,proto3" json:"drop_fraction,omitempty"`
+	// drop_ips are the addresses the "partial" action drops from the real answer, drop_count and
+	// drop_fraction are ignored if they are set
+	DropIps []string `protobuf:"bytes,21,rep,name=drop_ips,json=dropIps,proto3" json:"drop_ips,omitempty"`
+	// hotspot_ip is the endpoint the "hotspot" action pins the answers to, the first endpoint in the
+	// answer is used if it is empty or not in the answer
+	HotspotIp string `protobuf:"bytes,22,opt,name=hotspot_ip,json=hotspotIp,proto3" json:"hotspot_ip,omitempty"`
+	// misroute maps the source services to the target services of the "misroute" action, both in the
+	// form of SERVICE.NAMESPACE, e.g. {"orders.prod": "orders.staging"}. The A and AAAA lookups of a
+	// source service are answered with the cluster IP or the endpoints of its target service.
+	Misroute map[string]string `protobuf:"bytes,23,rep,name=misroute,proto3" json:"misroute,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// ttl is the TTL the "ttl" action rewrites the records of the real answer to, 0 defeats the caches
+	// of the clients and a huge value keeps the answer in them long after the experiment is canceled.
+	// It must not be greater than 2147483647.
+	Ttl uint32 `protobuf:"varint,24,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// chaos_ttl is the TTL of the records in the chaos answers of the "random", "cname", "cname_loop",
+	// "cname_chain" and "malformed" actions, the default value 10 is used if it is unset. It is used
+	// even if it is 0, so that the chaos answers aren't cached by the clients
+	ChaosTtl             *wrapperspb.UInt32Value `protobuf:"bytes,25,opt,name=chaos_ttl,json=chaosTtl,proto3" json:"chaos_ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
 func (m *SetDNSChaosRequest) Reset()         { *m = SetDNSChaosRequest{} }
 func (m *SetDNSChaosRequest) String() string { return proto.CompactTextString(m) }
 func (*SetDNSChaosRequest) ProtoMessage()    {}
 func (*SetDNSChaosRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dns_ad842bfaa9b8d3cd, []int{0}
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{0}
 }
 func (m *SetDNSChaosRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetDNSChaosRequest.Unmarshal(m, b)
@@ -111,6 +196,139 @@ func (m *SetDNSChaosRequest) GetPatterns() []string {
 	return nil
 }
 
+func (m *SetDNSChaosRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *SetDNSChaosRequest) GetRandomMode() string {
+	if m != nil {
+		return m.RandomMode
+	}
+	return ""
+}
+
+func (m *SetDNSChaosRequest) GetCnameTarget() string {
+	if m != nil {
+		return m.CnameTarget
+	}
+	return ""
+}
+
+func (m *SetDNSChaosRequest) GetCnameDepth() int32 {
+	if m != nil {
+		return m.CnameDepth
+	}
+	return 0
+}
+
+func (m *SetDNSChaosRequest) GetTruncatePartial() bool {
+	if m != nil {
+		return m.TruncatePartial
+	}
+	return false
+}
+
+func (m *SetDNSChaosRequest) GetTruncateTcpFail() bool {
+	if m != nil {
+		return m.TruncateTcpFail
+	}
+	return false
+}
+
+func (m *SetDNSChaosRequest) GetProtocol() string {
+	if m != nil {
+		return m.Protocol
+	}
+	return ""
+}
+
+func (m *SetDNSChaosRequest) GetQtypes() []string {
+	if m != nil {
+		return m.Qtypes
+	}
+	return nil
+}
+
+func (m *SetDNSChaosRequest) GetMalformedVariant() string {
+	if m != nil {
+		return m.MalformedVariant
+	}
+	return ""
+}
+
+func (m *SetDNSChaosRequest) GetMalformedSeed() int64 {
+	if m != nil {
+		return m.MalformedSeed
+	}
+	return 0
+}
+
+func (m *SetDNSChaosRequest) GetMutateModes() []string {
+	if m != nil {
+		return m.MutateModes
+	}
+	return nil
+}
+
+func (m *SetDNSChaosRequest) GetMutateTtl() uint32 {
+	if m != nil {
+		return m.MutateTtl
+	}
+	return 0
+}
+
+func (m *SetDNSChaosRequest) GetDropCount() int32 {
+	if m != nil {
+		return m.DropCount
+	}
+	return 0
+}
+
+func (m *SetDNSChaosRequest) GetDropFraction() float64 {
+	if m != nil {
+		return m.DropFraction
+	}
+	return 0
+}
+
+func (m *SetDNSChaosRequest) GetDropIps() []string {
+	if m != nil {
+		return m.DropIps
+	}
+	return nil
+}
+
+func (m *SetDNSChaosRequest) GetHotspotIp() string {
+	if m != nil {
+		return m.HotspotIp
+	}
+	return ""
+}
+
+func (m *SetDNSChaosRequest) GetMisroute() map[string]string {
+	if m != nil {
+		return m.Misroute
+	}
+	return nil
+}
+
+func (m *SetDNSChaosRequest) GetTtl() uint32 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+func (m *SetDNSChaosRequest) GetChaosTtl() *wrapperspb.UInt32Value {
+	if m != nil {
+		return m.ChaosTtl
+	}
+	return nil
+}
+
 type Pod struct {
 	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
@@ -123,7 +341,7 @@ func (m *Pod) Reset()         { *m = Pod{} }
 func (m *Pod) String() string { return proto.CompactTextString(m) }
 func (*Pod) ProtoMessage()    {}
 func (*Pod) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dns_ad842bfaa9b8d3cd, []int{1}
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{1}
 }
 func (m *Pod) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Pod.Unmarshal(m, b)
@@ -168,7 +386,7 @@ func (m *CancelDNSChaosRequest) Reset()         { *m = CancelDNSChaosRequest{} }
 func (m *CancelDNSChaosRequest) String() string { return proto.CompactTextString(m) }
 func (*CancelDNSChaosRequest) ProtoMessage()    {}
 func (*CancelDNSChaosRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dns_ad842bfaa9b8d3cd, []int{2}
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{2}
 }
 func (m *CancelDNSChaosRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CancelDNSChaosRequest.Unmarshal(m, b)
@@ -196,18 +414,22 @@ func (m *CancelDNSChaosRequest) GetName() string {
 }
 
 type DNSChaosResponse struct {
-	Result               bool     `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
-	Msg                  string   `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Result bool   `protobuf:"varint,1,opt,name=result,proto3" json:"result,omitempty"`
+	Msg    string `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	// generation is the generation of the experiment after a set or an update
+	Generation int64 `protobuf:"varint,3,opt,name=generation,proto3" json:"generation,omitempty"`
+	// stats are the final statistics of the experiment, only set by CancelDNSChaos
+	Stats                *DNSChaosStats `protobuf:"bytes,4,opt,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *DNSChaosResponse) Reset()         { *m = DNSChaosResponse{} }
 func (m *DNSChaosResponse) String() string { return proto.CompactTextString(m) }
 func (*DNSChaosResponse) ProtoMessage()    {}
 func (*DNSChaosResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_dns_ad842bfaa9b8d3cd, []int{3}
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{3}
 }
 func (m *DNSChaosResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DNSChaosResponse.Unmarshal(m, b)
@@ -241,102 +463,1139 @@ func (m *DNSChaosResponse) GetMsg() string {
 	return ""
 }
 
-func init() {
-	proto.RegisterType((*SetDNSChaosRequest)(nil), "pb.SetDNSChaosRequest")
-	proto.RegisterType((*Pod)(nil), "pb.Pod")
-	proto.RegisterType((*CancelDNSChaosRequest)(nil), "pb.CancelDNSChaosRequest")
-	proto.RegisterType((*DNSChaosResponse)(nil), "pb.DNSChaosResponse")
+func (m *DNSChaosResponse) GetGeneration() int64 {
+	if m != nil {
+		return m.Generation
+	}
+	return 0
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *DNSChaosResponse) GetStats() *DNSChaosStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+type UpdateDNSChaosRequest struct {
+	// chaos is the new definition of the experiment, the name must be an existing one
+	Chaos *SetDNSChaosRequest `protobuf:"bytes,1,opt,name=chaos,proto3" json:"chaos,omitempty"`
+	// generation must equal the current generation of the experiment, otherwise the update is
+	// aborted, so that concurrent updates can't clobber each other. 0 skips the check.
+	Generation           int64    `protobuf:"varint,2,opt,name=generation,proto3" json:"generation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
 
-// DNSClient is the client API for DNS service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type DNSClient interface {
-	SetDNSChaos(ctx context.Context, in *SetDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error)
-	CancelDNSChaos(ctx context.Context, in *CancelDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error)
+func (m *UpdateDNSChaosRequest) Reset()         { *m = UpdateDNSChaosRequest{} }
+func (m *UpdateDNSChaosRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateDNSChaosRequest) ProtoMessage()    {}
+func (*UpdateDNSChaosRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{4}
+}
+func (m *UpdateDNSChaosRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateDNSChaosRequest.Unmarshal(m, b)
+}
+func (m *UpdateDNSChaosRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateDNSChaosRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateDNSChaosRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateDNSChaosRequest.Merge(dst, src)
+}
+func (m *UpdateDNSChaosRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateDNSChaosRequest.Size(m)
+}
+func (m *UpdateDNSChaosRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateDNSChaosRequest.DiscardUnknown(m)
 }
 
-type dNSClient struct {
-	cc *grpc.ClientConn
+var xxx_messageInfo_UpdateDNSChaosRequest proto.InternalMessageInfo
+
+func (m *UpdateDNSChaosRequest) GetChaos() *SetDNSChaosRequest {
+	if m != nil {
+		return m.Chaos
+	}
+	return nil
 }
 
-func NewDNSClient(cc *grpc.ClientConn) DNSClient {
-	return &dNSClient{cc}
+func (m *UpdateDNSChaosRequest) GetGeneration() int64 {
+	if m != nil {
+		return m.Generation
+	}
+	return 0
 }
 
-func (c *dNSClient) SetDNSChaos(ctx context.Context, in *SetDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error) {
-	out := new(DNSChaosResponse)
-	err := c.cc.Invoke(ctx, "/pb.DNS/SetDNSChaos", in, out, opts...)
-	if err != nil {
-		return nil, err
+type ListDNSChaosRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDNSChaosRequest) Reset()         { *m = ListDNSChaosRequest{} }
+func (m *ListDNSChaosRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDNSChaosRequest) ProtoMessage()    {}
+func (*ListDNSChaosRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{5}
+}
+func (m *ListDNSChaosRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDNSChaosRequest.Unmarshal(m, b)
+}
+func (m *ListDNSChaosRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDNSChaosRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListDNSChaosRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDNSChaosRequest.Merge(dst, src)
+}
+func (m *ListDNSChaosRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDNSChaosRequest.Size(m)
+}
+func (m *ListDNSChaosRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDNSChaosRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDNSChaosRequest proto.InternalMessageInfo
+
+type ListDNSChaosResponse struct {
+	Experiments []*DNSChaosInfo `protobuf:"bytes,1,rep,name=experiments,proto3" json:"experiments,omitempty"`
+	// pause tells whether chaos is paused by PauseAll
+	Pause                *PauseStatus `protobuf:"bytes,2,opt,name=pause,proto3" json:"pause,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ListDNSChaosResponse) Reset()         { *m = ListDNSChaosResponse{} }
+func (m *ListDNSChaosResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDNSChaosResponse) ProtoMessage()    {}
+func (*ListDNSChaosResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{6}
+}
+func (m *ListDNSChaosResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDNSChaosResponse.Unmarshal(m, b)
+}
+func (m *ListDNSChaosResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDNSChaosResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListDNSChaosResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDNSChaosResponse.Merge(dst, src)
+}
+func (m *ListDNSChaosResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDNSChaosResponse.Size(m)
+}
+func (m *ListDNSChaosResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDNSChaosResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDNSChaosResponse proto.InternalMessageInfo
+
+func (m *ListDNSChaosResponse) GetExperiments() []*DNSChaosInfo {
+	if m != nil {
+		return m.Experiments
 	}
-	return out, nil
+	return nil
 }
 
-func (c *dNSClient) CancelDNSChaos(ctx context.Context, in *CancelDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error) {
-	out := new(DNSChaosResponse)
-	err := c.cc.Invoke(ctx, "/pb.DNS/CancelDNSChaos", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ListDNSChaosResponse) GetPause() *PauseStatus {
+	if m != nil {
+		return m.Pause
 	}
-	return out, nil
+	return nil
 }
 
-// DNSServer is the server API for DNS service.
-type DNSServer interface {
-	SetDNSChaos(context.Context, *SetDNSChaosRequest) (*DNSChaosResponse, error)
-	CancelDNSChaos(context.Context, *CancelDNSChaosRequest) (*DNSChaosResponse, error)
+type GetDNSChaosRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func RegisterDNSServer(s *grpc.Server, srv DNSServer) {
-	s.RegisterService(&_DNS_serviceDesc, srv)
+func (m *GetDNSChaosRequest) Reset()         { *m = GetDNSChaosRequest{} }
+func (m *GetDNSChaosRequest) String() string { return proto.CompactTextString(m) }
+func (*GetDNSChaosRequest) ProtoMessage()    {}
+func (*GetDNSChaosRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{7}
+}
+func (m *GetDNSChaosRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDNSChaosRequest.Unmarshal(m, b)
+}
+func (m *GetDNSChaosRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDNSChaosRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetDNSChaosRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDNSChaosRequest.Merge(dst, src)
+}
+func (m *GetDNSChaosRequest) XXX_Size() int {
+	return xxx_messageInfo_GetDNSChaosRequest.Size(m)
+}
+func (m *GetDNSChaosRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDNSChaosRequest.DiscardUnknown(m)
 }
 
-func _DNS_SetDNSChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SetDNSChaosRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(DNSServer).SetDNSChaos(ctx, in)
+var xxx_messageInfo_GetDNSChaosRequest proto.InternalMessageInfo
+
+func (m *GetDNSChaosRequest) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.DNS/SetDNSChaos",
+	return ""
+}
+
+type DNSChaosInfo struct {
+	// definition is the request which set the experiment
+	Definition *SetDNSChaosRequest `protobuf:"bytes,1,opt,name=definition,proto3" json:"definition,omitempty"`
+	// pods are the pods in the definition, the ip is empty if chaos is not applied to the pod,
+	// for example the pod is taken over by a later experiment
+	Pods       []*PodStatus           `protobuf:"bytes,2,rep,name=pods,proto3" json:"pods,omitempty"`
+	CreateTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	// hits is the count of DNS requests which chaos is injected into
+	Hits       int64 `protobuf:"varint,4,opt,name=hits,proto3" json:"hits,omitempty"`
+	Generation int64 `protobuf:"varint,5,opt,name=generation,proto3" json:"generation,omitempty"`
+	// dry_run_hits is the count of DNS requests which chaos would be injected into in dry run
+	DryRunHits           int64    `protobuf:"varint,6,opt,name=dry_run_hits,json=dryRunHits,proto3" json:"dry_run_hits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DNSChaosInfo) Reset()         { *m = DNSChaosInfo{} }
+func (m *DNSChaosInfo) String() string { return proto.CompactTextString(m) }
+func (*DNSChaosInfo) ProtoMessage()    {}
+func (*DNSChaosInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{8}
+}
+func (m *DNSChaosInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DNSChaosInfo.Unmarshal(m, b)
+}
+func (m *DNSChaosInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DNSChaosInfo.Marshal(b, m, deterministic)
+}
+func (dst *DNSChaosInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DNSChaosInfo.Merge(dst, src)
+}
+func (m *DNSChaosInfo) XXX_Size() int {
+	return xxx_messageInfo_DNSChaosInfo.Size(m)
+}
+func (m *DNSChaosInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_DNSChaosInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DNSChaosInfo proto.InternalMessageInfo
+
+func (m *DNSChaosInfo) GetDefinition() *SetDNSChaosRequest {
+	if m != nil {
+		return m.Definition
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DNSServer).SetDNSChaos(ctx, req.(*SetDNSChaosRequest))
+	return nil
+}
+
+func (m *DNSChaosInfo) GetPods() []*PodStatus {
+	if m != nil {
+		return m.Pods
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _DNS_CancelDNSChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CancelDNSChaosRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *DNSChaosInfo) GetCreateTime() *timestamppb.Timestamp {
+	if m != nil {
+		return m.CreateTime
 	}
-	if interceptor == nil {
-		return srv.(DNSServer).CancelDNSChaos(ctx, in)
+	return nil
+}
+
+func (m *DNSChaosInfo) GetHits() int64 {
+	if m != nil {
+		return m.Hits
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/pb.DNS/CancelDNSChaos",
+	return 0
+}
+
+func (m *DNSChaosInfo) GetGeneration() int64 {
+	if m != nil {
+		return m.Generation
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DNSServer).CancelDNSChaos(ctx, req.(*CancelDNSChaosRequest))
+	return 0
+}
+
+func (m *DNSChaosInfo) GetDryRunHits() int64 {
+	if m != nil {
+		return m.DryRunHits
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-var _DNS_serviceDesc = grpc.ServiceDesc{
+type PodStatus struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Ip                   string   `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PodStatus) Reset()         { *m = PodStatus{} }
+func (m *PodStatus) String() string { return proto.CompactTextString(m) }
+func (*PodStatus) ProtoMessage()    {}
+func (*PodStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{9}
+}
+func (m *PodStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PodStatus.Unmarshal(m, b)
+}
+func (m *PodStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PodStatus.Marshal(b, m, deterministic)
+}
+func (dst *PodStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PodStatus.Merge(dst, src)
+}
+func (m *PodStatus) XXX_Size() int {
+	return xxx_messageInfo_PodStatus.Size(m)
+}
+func (m *PodStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_PodStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PodStatus proto.InternalMessageInfo
+
+func (m *PodStatus) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PodStatus) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PodStatus) GetIp() string {
+	if m != nil {
+		return m.Ip
+	}
+	return ""
+}
+
+type WatchDNSChaosEventsRequest struct {
+	// experiments filters the events by the experiment names, the events of all experiments are sent if it is empty
+	Experiments          []string `protobuf:"bytes,1,rep,name=experiments,proto3" json:"experiments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchDNSChaosEventsRequest) Reset()         { *m = WatchDNSChaosEventsRequest{} }
+func (m *WatchDNSChaosEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchDNSChaosEventsRequest) ProtoMessage()    {}
+func (*WatchDNSChaosEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{10}
+}
+func (m *WatchDNSChaosEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchDNSChaosEventsRequest.Unmarshal(m, b)
+}
+func (m *WatchDNSChaosEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchDNSChaosEventsRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchDNSChaosEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchDNSChaosEventsRequest.Merge(dst, src)
+}
+func (m *WatchDNSChaosEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchDNSChaosEventsRequest.Size(m)
+}
+func (m *WatchDNSChaosEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchDNSChaosEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchDNSChaosEventsRequest proto.InternalMessageInfo
+
+func (m *WatchDNSChaosEventsRequest) GetExperiments() []string {
+	if m != nil {
+		return m.Experiments
+	}
+	return nil
+}
+
+// DNSChaosEvent is sent for every DNS request matched by an experiment, including the ones served
+// with the real answer because the experiment is in dry run or chaos is paused
+type DNSChaosEvent struct {
+	Time       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Experiment string                 `protobuf:"bytes,2,opt,name=experiment,proto3" json:"experiment,omitempty"`
+	// pod is the client pod which sent the DNS request
+	Pod    *Pod   `protobuf:"bytes,3,opt,name=pod,proto3" json:"pod,omitempty"`
+	Qname  string `protobuf:"bytes,4,opt,name=qname,proto3" json:"qname,omitempty"`
+	Qtype  string `protobuf:"bytes,5,opt,name=qtype,proto3" json:"qtype,omitempty"`
+	Action string `protobuf:"bytes,6,opt,name=action,proto3" json:"action,omitempty"`
+	// rcode and answers are the response returned to the client, which is the real answer unless chaos is
+	// injected, the answers are in presentation format
+	Rcode   string   `protobuf:"bytes,7,opt,name=rcode,proto3" json:"rcode,omitempty"`
+	Answers []string `protobuf:"bytes,8,rep,name=answers,proto3" json:"answers,omitempty"`
+	// dropped is the count of events dropped before this one because the subscriber was too slow
+	Dropped uint64 `protobuf:"varint,9,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	// decision is "injected" if chaos is injected, "dry_run" or "paused" if the real answer is returned
+	Decision             string   `protobuf:"bytes,10,opt,name=decision,proto3" json:"decision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DNSChaosEvent) Reset()         { *m = DNSChaosEvent{} }
+func (m *DNSChaosEvent) String() string { return proto.CompactTextString(m) }
+func (*DNSChaosEvent) ProtoMessage()    {}
+func (*DNSChaosEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{11}
+}
+func (m *DNSChaosEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DNSChaosEvent.Unmarshal(m, b)
+}
+func (m *DNSChaosEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DNSChaosEvent.Marshal(b, m, deterministic)
+}
+func (dst *DNSChaosEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DNSChaosEvent.Merge(dst, src)
+}
+func (m *DNSChaosEvent) XXX_Size() int {
+	return xxx_messageInfo_DNSChaosEvent.Size(m)
+}
+func (m *DNSChaosEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_DNSChaosEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DNSChaosEvent proto.InternalMessageInfo
+
+func (m *DNSChaosEvent) GetTime() *timestamppb.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *DNSChaosEvent) GetExperiment() string {
+	if m != nil {
+		return m.Experiment
+	}
+	return ""
+}
+
+func (m *DNSChaosEvent) GetPod() *Pod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+func (m *DNSChaosEvent) GetQname() string {
+	if m != nil {
+		return m.Qname
+	}
+	return ""
+}
+
+func (m *DNSChaosEvent) GetQtype() string {
+	if m != nil {
+		return m.Qtype
+	}
+	return ""
+}
+
+func (m *DNSChaosEvent) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *DNSChaosEvent) GetRcode() string {
+	if m != nil {
+		return m.Rcode
+	}
+	return ""
+}
+
+func (m *DNSChaosEvent) GetAnswers() []string {
+	if m != nil {
+		return m.Answers
+	}
+	return nil
+}
+
+func (m *DNSChaosEvent) GetDropped() uint64 {
+	if m != nil {
+		return m.Dropped
+	}
+	return 0
+}
+
+func (m *DNSChaosEvent) GetDecision() string {
+	if m != nil {
+		return m.Decision
+	}
+	return ""
+}
+
+type PauseAllRequest struct {
+	// reason is recorded with the pause, for example the ID of the incident. If chaos is already
+	// paused, a non-empty reason replaces the recorded one and the pause time is kept
+	Reason               string   `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PauseAllRequest) Reset()         { *m = PauseAllRequest{} }
+func (m *PauseAllRequest) String() string { return proto.CompactTextString(m) }
+func (*PauseAllRequest) ProtoMessage()    {}
+func (*PauseAllRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{12}
+}
+func (m *PauseAllRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseAllRequest.Unmarshal(m, b)
+}
+func (m *PauseAllRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseAllRequest.Marshal(b, m, deterministic)
+}
+func (dst *PauseAllRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseAllRequest.Merge(dst, src)
+}
+func (m *PauseAllRequest) XXX_Size() int {
+	return xxx_messageInfo_PauseAllRequest.Size(m)
+}
+func (m *PauseAllRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseAllRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PauseAllRequest proto.InternalMessageInfo
+
+func (m *PauseAllRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type ResumeAllRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResumeAllRequest) Reset()         { *m = ResumeAllRequest{} }
+func (m *ResumeAllRequest) String() string { return proto.CompactTextString(m) }
+func (*ResumeAllRequest) ProtoMessage()    {}
+func (*ResumeAllRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{13}
+}
+func (m *ResumeAllRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeAllRequest.Unmarshal(m, b)
+}
+func (m *ResumeAllRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeAllRequest.Marshal(b, m, deterministic)
+}
+func (dst *ResumeAllRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeAllRequest.Merge(dst, src)
+}
+func (m *ResumeAllRequest) XXX_Size() int {
+	return xxx_messageInfo_ResumeAllRequest.Size(m)
+}
+func (m *ResumeAllRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeAllRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResumeAllRequest proto.InternalMessageInfo
+
+type PauseStatus struct {
+	Paused               bool                   `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	Reason               string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	PauseTime            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=pause_time,json=pauseTime,proto3" json:"pause_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *PauseStatus) Reset()         { *m = PauseStatus{} }
+func (m *PauseStatus) String() string { return proto.CompactTextString(m) }
+func (*PauseStatus) ProtoMessage()    {}
+func (*PauseStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{14}
+}
+func (m *PauseStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseStatus.Unmarshal(m, b)
+}
+func (m *PauseStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseStatus.Marshal(b, m, deterministic)
+}
+func (dst *PauseStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseStatus.Merge(dst, src)
+}
+func (m *PauseStatus) XXX_Size() int {
+	return xxx_messageInfo_PauseStatus.Size(m)
+}
+func (m *PauseStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PauseStatus proto.InternalMessageInfo
+
+func (m *PauseStatus) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+func (m *PauseStatus) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *PauseStatus) GetPauseTime() *timestamppb.Timestamp {
+	if m != nil {
+		return m.PauseTime
+	}
+	return nil
+}
+
+type GetDNSChaosStatsRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetDNSChaosStatsRequest) Reset()         { *m = GetDNSChaosStatsRequest{} }
+func (m *GetDNSChaosStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetDNSChaosStatsRequest) ProtoMessage()    {}
+func (*GetDNSChaosStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{15}
+}
+func (m *GetDNSChaosStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDNSChaosStatsRequest.Unmarshal(m, b)
+}
+func (m *GetDNSChaosStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDNSChaosStatsRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetDNSChaosStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDNSChaosStatsRequest.Merge(dst, src)
+}
+func (m *GetDNSChaosStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetDNSChaosStatsRequest.Size(m)
+}
+func (m *GetDNSChaosStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDNSChaosStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDNSChaosStatsRequest proto.InternalMessageInfo
+
+func (m *GetDNSChaosStatsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// DNSChaosStats are the statistics of an experiment since it was set
+type DNSChaosStats struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// matched is the count of DNS requests matched by the experiment, including the ones served
+	// with the real answers in dry run or while chaos is paused
+	Matched int64 `protobuf:"varint,2,opt,name=matched,proto3" json:"matched,omitempty"`
+	// injected is the count of DNS requests which chaos is injected into
+	Injected int64 `protobuf:"varint,3,opt,name=injected,proto3" json:"injected,omitempty"`
+	// injected_by_action is injected by action, it has more than one entry if the action is
+	// changed by UpdateDNSChaos
+	InjectedByAction map[string]int64 `protobuf:"bytes,4,rep,name=injected_by_action,json=injectedByAction,proto3" json:"injected_by_action,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	DryRunHits       int64            `protobuf:"varint,5,opt,name=dry_run_hits,json=dryRunHits,proto3" json:"dry_run_hits,omitempty"`
+	// pod_hits are the counts of DNS requests which chaos is injected into by client pod, sorted by
+	// namespace and name
+	PodHits []*PodHits `protobuf:"bytes,6,rep,name=pod_hits,json=podHits,proto3" json:"pod_hits,omitempty"`
+	// first_injection_time and last_injection_time are unset if chaos is never injected
+	FirstInjectionTime   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=first_injection_time,json=firstInjectionTime,proto3" json:"first_injection_time,omitempty"`
+	LastInjectionTime    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_injection_time,json=lastInjectionTime,proto3" json:"last_injection_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *DNSChaosStats) Reset()         { *m = DNSChaosStats{} }
+func (m *DNSChaosStats) String() string { return proto.CompactTextString(m) }
+func (*DNSChaosStats) ProtoMessage()    {}
+func (*DNSChaosStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{16}
+}
+func (m *DNSChaosStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DNSChaosStats.Unmarshal(m, b)
+}
+func (m *DNSChaosStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DNSChaosStats.Marshal(b, m, deterministic)
+}
+func (dst *DNSChaosStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DNSChaosStats.Merge(dst, src)
+}
+func (m *DNSChaosStats) XXX_Size() int {
+	return xxx_messageInfo_DNSChaosStats.Size(m)
+}
+func (m *DNSChaosStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_DNSChaosStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DNSChaosStats proto.InternalMessageInfo
+
+func (m *DNSChaosStats) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DNSChaosStats) GetMatched() int64 {
+	if m != nil {
+		return m.Matched
+	}
+	return 0
+}
+
+func (m *DNSChaosStats) GetInjected() int64 {
+	if m != nil {
+		return m.Injected
+	}
+	return 0
+}
+
+func (m *DNSChaosStats) GetInjectedByAction() map[string]int64 {
+	if m != nil {
+		return m.InjectedByAction
+	}
+	return nil
+}
+
+func (m *DNSChaosStats) GetDryRunHits() int64 {
+	if m != nil {
+		return m.DryRunHits
+	}
+	return 0
+}
+
+func (m *DNSChaosStats) GetPodHits() []*PodHits {
+	if m != nil {
+		return m.PodHits
+	}
+	return nil
+}
+
+func (m *DNSChaosStats) GetFirstInjectionTime() *timestamppb.Timestamp {
+	if m != nil {
+		return m.FirstInjectionTime
+	}
+	return nil
+}
+
+func (m *DNSChaosStats) GetLastInjectionTime() *timestamppb.Timestamp {
+	if m != nil {
+		return m.LastInjectionTime
+	}
+	return nil
+}
+
+type PodHits struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Hits                 int64    `protobuf:"varint,3,opt,name=hits,proto3" json:"hits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PodHits) Reset()         { *m = PodHits{} }
+func (m *PodHits) String() string { return proto.CompactTextString(m) }
+func (*PodHits) ProtoMessage()    {}
+func (*PodHits) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dns_67ea023cf398fb1a, []int{17}
+}
+func (m *PodHits) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PodHits.Unmarshal(m, b)
+}
+func (m *PodHits) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PodHits.Marshal(b, m, deterministic)
+}
+func (dst *PodHits) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PodHits.Merge(dst, src)
+}
+func (m *PodHits) XXX_Size() int {
+	return xxx_messageInfo_PodHits.Size(m)
+}
+func (m *PodHits) XXX_DiscardUnknown() {
+	xxx_messageInfo_PodHits.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PodHits proto.InternalMessageInfo
+
+func (m *PodHits) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *PodHits) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PodHits) GetHits() int64 {
+	if m != nil {
+		return m.Hits
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*SetDNSChaosRequest)(nil), "pb.SetDNSChaosRequest")
+	proto.RegisterMapType((map[string]string)(nil), "pb.SetDNSChaosRequest.MisrouteEntry")
+	proto.RegisterType((*Pod)(nil), "pb.Pod")
+	proto.RegisterType((*CancelDNSChaosRequest)(nil), "pb.CancelDNSChaosRequest")
+	proto.RegisterType((*DNSChaosResponse)(nil), "pb.DNSChaosResponse")
+	proto.RegisterType((*UpdateDNSChaosRequest)(nil), "pb.UpdateDNSChaosRequest")
+	proto.RegisterType((*ListDNSChaosRequest)(nil), "pb.ListDNSChaosRequest")
+	proto.RegisterType((*ListDNSChaosResponse)(nil), "pb.ListDNSChaosResponse")
+	proto.RegisterType((*GetDNSChaosRequest)(nil), "pb.GetDNSChaosRequest")
+	proto.RegisterType((*DNSChaosInfo)(nil), "pb.DNSChaosInfo")
+	proto.RegisterType((*PodStatus)(nil), "pb.PodStatus")
+	proto.RegisterType((*WatchDNSChaosEventsRequest)(nil), "pb.WatchDNSChaosEventsRequest")
+	proto.RegisterType((*DNSChaosEvent)(nil), "pb.DNSChaosEvent")
+	proto.RegisterType((*PauseAllRequest)(nil), "pb.PauseAllRequest")
+	proto.RegisterType((*ResumeAllRequest)(nil), "pb.ResumeAllRequest")
+	proto.RegisterType((*PauseStatus)(nil), "pb.PauseStatus")
+	proto.RegisterType((*GetDNSChaosStatsRequest)(nil), "pb.GetDNSChaosStatsRequest")
+	proto.RegisterType((*DNSChaosStats)(nil), "pb.DNSChaosStats")
+	proto.RegisterMapType((map[string]int64)(nil), "pb.DNSChaosStats.InjectedByActionEntry")
+	proto.RegisterType((*PodHits)(nil), "pb.PodHits")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// DNSClient is the client API for DNS service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DNSClient interface {
+	SetDNSChaos(ctx context.Context, in *SetDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error)
+	CancelDNSChaos(ctx context.Context, in *CancelDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error)
+	ListDNSChaos(ctx context.Context, in *ListDNSChaosRequest, opts ...grpc.CallOption) (*ListDNSChaosResponse, error)
+	GetDNSChaos(ctx context.Context, in *GetDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosInfo, error)
+	UpdateDNSChaos(ctx context.Context, in *UpdateDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error)
+	WatchDNSChaosEvents(ctx context.Context, in *WatchDNSChaosEventsRequest, opts ...grpc.CallOption) (DNS_WatchDNSChaosEventsClient, error)
+	// PauseAll stops injecting chaos of all the experiments without deleting them, until ResumeAll is called
+	PauseAll(ctx context.Context, in *PauseAllRequest, opts ...grpc.CallOption) (*PauseStatus, error)
+	ResumeAll(ctx context.Context, in *ResumeAllRequest, opts ...grpc.CallOption) (*PauseStatus, error)
+	GetDNSChaosStats(ctx context.Context, in *GetDNSChaosStatsRequest, opts ...grpc.CallOption) (*DNSChaosStats, error)
+}
+
+type dNSClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDNSClient(cc *grpc.ClientConn) DNSClient {
+	return &dNSClient{cc}
+}
+
+func (c *dNSClient) SetDNSChaos(ctx context.Context, in *SetDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error) {
+	out := new(DNSChaosResponse)
+	err := c.cc.Invoke(ctx, "/pb.DNS/SetDNSChaos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSClient) CancelDNSChaos(ctx context.Context, in *CancelDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error) {
+	out := new(DNSChaosResponse)
+	err := c.cc.Invoke(ctx, "/pb.DNS/CancelDNSChaos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSClient) ListDNSChaos(ctx context.Context, in *ListDNSChaosRequest, opts ...grpc.CallOption) (*ListDNSChaosResponse, error) {
+	out := new(ListDNSChaosResponse)
+	err := c.cc.Invoke(ctx, "/pb.DNS/ListDNSChaos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSClient) GetDNSChaos(ctx context.Context, in *GetDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosInfo, error) {
+	out := new(DNSChaosInfo)
+	err := c.cc.Invoke(ctx, "/pb.DNS/GetDNSChaos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSClient) UpdateDNSChaos(ctx context.Context, in *UpdateDNSChaosRequest, opts ...grpc.CallOption) (*DNSChaosResponse, error) {
+	out := new(DNSChaosResponse)
+	err := c.cc.Invoke(ctx, "/pb.DNS/UpdateDNSChaos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSClient) WatchDNSChaosEvents(ctx context.Context, in *WatchDNSChaosEventsRequest, opts ...grpc.CallOption) (DNS_WatchDNSChaosEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DNS_serviceDesc.Streams[0], "/pb.DNS/WatchDNSChaosEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dNSWatchDNSChaosEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DNS_WatchDNSChaosEventsClient interface {
+	Recv() (*DNSChaosEvent, error)
+	grpc.ClientStream
+}
+
+type dNSWatchDNSChaosEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *dNSWatchDNSChaosEventsClient) Recv() (*DNSChaosEvent, error) {
+	m := new(DNSChaosEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dNSClient) PauseAll(ctx context.Context, in *PauseAllRequest, opts ...grpc.CallOption) (*PauseStatus, error) {
+	out := new(PauseStatus)
+	err := c.cc.Invoke(ctx, "/pb.DNS/PauseAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSClient) ResumeAll(ctx context.Context, in *ResumeAllRequest, opts ...grpc.CallOption) (*PauseStatus, error) {
+	out := new(PauseStatus)
+	err := c.cc.Invoke(ctx, "/pb.DNS/ResumeAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSClient) GetDNSChaosStats(ctx context.Context, in *GetDNSChaosStatsRequest, opts ...grpc.CallOption) (*DNSChaosStats, error) {
+	out := new(DNSChaosStats)
+	err := c.cc.Invoke(ctx, "/pb.DNS/GetDNSChaosStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DNSServer is the server API for DNS service.
+type DNSServer interface {
+	SetDNSChaos(context.Context, *SetDNSChaosRequest) (*DNSChaosResponse, error)
+	CancelDNSChaos(context.Context, *CancelDNSChaosRequest) (*DNSChaosResponse, error)
+	ListDNSChaos(context.Context, *ListDNSChaosRequest) (*ListDNSChaosResponse, error)
+	GetDNSChaos(context.Context, *GetDNSChaosRequest) (*DNSChaosInfo, error)
+	UpdateDNSChaos(context.Context, *UpdateDNSChaosRequest) (*DNSChaosResponse, error)
+	WatchDNSChaosEvents(*WatchDNSChaosEventsRequest, DNS_WatchDNSChaosEventsServer) error
+	// PauseAll stops injecting chaos of all the experiments without deleting them, until ResumeAll is called
+	PauseAll(context.Context, *PauseAllRequest) (*PauseStatus, error)
+	ResumeAll(context.Context, *ResumeAllRequest) (*PauseStatus, error)
+	GetDNSChaosStats(context.Context, *GetDNSChaosStatsRequest) (*DNSChaosStats, error)
+}
+
+func RegisterDNSServer(s *grpc.Server, srv DNSServer) {
+	s.RegisterService(&_DNS_serviceDesc, srv)
+}
+
+func _DNS_SetDNSChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDNSChaosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServer).SetDNSChaos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.DNS/SetDNSChaos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServer).SetDNSChaos(ctx, req.(*SetDNSChaosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNS_CancelDNSChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelDNSChaosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServer).CancelDNSChaos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.DNS/CancelDNSChaos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServer).CancelDNSChaos(ctx, req.(*CancelDNSChaosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNS_ListDNSChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDNSChaosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServer).ListDNSChaos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.DNS/ListDNSChaos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServer).ListDNSChaos(ctx, req.(*ListDNSChaosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNS_GetDNSChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDNSChaosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServer).GetDNSChaos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.DNS/GetDNSChaos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServer).GetDNSChaos(ctx, req.(*GetDNSChaosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNS_UpdateDNSChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDNSChaosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServer).UpdateDNSChaos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.DNS/UpdateDNSChaos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServer).UpdateDNSChaos(ctx, req.(*UpdateDNSChaosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNS_WatchDNSChaosEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDNSChaosEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DNSServer).WatchDNSChaosEvents(m, &dNSWatchDNSChaosEventsServer{stream})
+}
+
+type DNS_WatchDNSChaosEventsServer interface {
+	Send(*DNSChaosEvent) error
+	grpc.ServerStream
+}
+
+type dNSWatchDNSChaosEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *dNSWatchDNSChaosEventsServer) Send(m *DNSChaosEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DNS_PauseAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServer).PauseAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.DNS/PauseAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServer).PauseAll(ctx, req.(*PauseAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNS_ResumeAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServer).ResumeAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.DNS/ResumeAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServer).ResumeAll(ctx, req.(*ResumeAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNS_GetDNSChaosStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDNSChaosStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServer).GetDNSChaosStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.DNS/GetDNSChaosStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServer).GetDNSChaosStats(ctx, req.(*GetDNSChaosStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DNS_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.DNS",
 	HandlerType: (*DNSServer)(nil),
 	Methods: []grpc.MethodDesc{
@@ -348,31 +1607,131 @@ var _DNS_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CancelDNSChaos",
 			Handler:    _DNS_CancelDNSChaos_Handler,
 		},
+		{
+			MethodName: "ListDNSChaos",
+			Handler:    _DNS_ListDNSChaos_Handler,
+		},
+		{
+			MethodName: "GetDNSChaos",
+			Handler:    _DNS_GetDNSChaos_Handler,
+		},
+		{
+			MethodName: "UpdateDNSChaos",
+			Handler:    _DNS_UpdateDNSChaos_Handler,
+		},
+		{
+			MethodName: "PauseAll",
+			Handler:    _DNS_PauseAll_Handler,
+		},
+		{
+			MethodName: "ResumeAll",
+			Handler:    _DNS_ResumeAll_Handler,
+		},
+		{
+			MethodName: "GetDNSChaosStats",
+			Handler:    _DNS_GetDNSChaosStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDNSChaosEvents",
+			Handler:       _DNS_WatchDNSChaosEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "dns.proto",
-}
-
-func init() { proto.RegisterFile("dns.proto", fileDescriptor_dns_ad842bfaa9b8d3cd) }
-
-var fileDescriptor_dns_ad842bfaa9b8d3cd = []byte{
-	// 285 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x51, 0xcd, 0x4e, 0xf3, 0x30,
-	0x10, 0xfc, 0x52, 0xa7, 0xf9, 0x9a, 0xad, 0x84, 0xaa, 0x55, 0xa9, 0x4c, 0xe1, 0x10, 0xe5, 0x54,
-	0x09, 0x29, 0x87, 0x72, 0xe0, 0x02, 0xa7, 0xf4, 0x5c, 0x55, 0xc9, 0x13, 0x38, 0x89, 0x05, 0x48,
-	0xa9, 0x6d, 0xb2, 0xee, 0x43, 0xf0, 0x2c, 0xbc, 0x24, 0xb2, 0x49, 0x1b, 0x7e, 0x25, 0x6e, 0x3b,
-	0xb3, 0x93, 0xec, 0xcc, 0x18, 0xe2, 0x46, 0x51, 0x66, 0x3a, 0x6d, 0x35, 0x8e, 0x4c, 0x95, 0xbe,
-	0x06, 0x80, 0xa5, 0xb4, 0x9b, 0x6d, 0x99, 0x3f, 0x0a, 0x4d, 0x85, 0x7c, 0x3e, 0x48, 0xb2, 0x88,
-	0x10, 0x2a, 0xb1, 0x97, 0x3c, 0x48, 0x82, 0x55, 0x5c, 0xf8, 0x19, 0x2f, 0x21, 0x34, 0xba, 0x21,
-	0x3e, 0x4a, 0xd8, 0x6a, 0xba, 0xfe, 0x9f, 0x99, 0x2a, 0xdb, 0xe9, 0xa6, 0xf0, 0x24, 0x2e, 0x20,
-	0x12, 0xb5, 0x7d, 0xd2, 0x8a, 0x33, 0xff, 0x49, 0x8f, 0x70, 0x0e, 0x63, 0xaa, 0xb5, 0x91, 0x3c,
-	0xf4, 0xf4, 0x3b, 0xc0, 0x25, 0x4c, 0x48, 0xb6, 0xb2, 0xb6, 0xba, 0xe3, 0x63, 0xbf, 0x38, 0x61,
-	0xb7, 0x33, 0xc2, 0x5a, 0xd9, 0x29, 0xe2, 0x51, 0xc2, 0xdc, 0xee, 0x88, 0xd3, 0x5b, 0x60, 0x3b,
-	0xdd, 0xe0, 0x15, 0xc4, 0xce, 0x11, 0x19, 0x51, 0x1f, 0x2d, 0x0e, 0xc4, 0xc9, 0xfb, 0x68, 0xf0,
-	0x9e, 0x5e, 0xc3, 0x79, 0x2e, 0x54, 0x2d, 0xdb, 0x3f, 0x04, 0x4d, 0xef, 0x60, 0x36, 0xc8, 0xc8,
-	0x68, 0x45, 0xd2, 0xe5, 0xeb, 0x24, 0x1d, 0x5a, 0xeb, 0x95, 0x93, 0xa2, 0x47, 0x38, 0x03, 0xb6,
-	0xa7, 0x87, 0xfe, 0x96, 0x1b, 0xd7, 0x2f, 0x01, 0xb0, 0xcd, 0xb6, 0xc4, 0x7b, 0x98, 0x7e, 0x28,
-	0x16, 0x17, 0xae, 0xaf, 0xef, 0x4d, 0x2f, 0xe7, 0x8e, 0xff, 0x7a, 0x2e, 0xfd, 0x87, 0x39, 0x9c,
-	0x7d, 0x76, 0x8c, 0x17, 0x4e, 0xf9, 0x63, 0x8a, 0xdf, 0x7e, 0x52, 0x45, 0xfe, 0xa1, 0x6f, 0xde,
-	0x02, 0x00, 0x00, 0xff, 0xff, 0x6d, 0x16, 0x0c, 0xd6, 0xf5, 0x01, 0x00, 0x00,
+	Metadata: "pb/dns.proto",
+}
+
+func init() { proto.RegisterFile("pb/dns.proto", fileDescriptor_dns_67ea023cf398fb1a) }
+
+var fileDescriptor_dns_67ea023cf398fb1a = []byte{
+	// 1408 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xef, 0x72, 0xdb, 0x44,
+	0x10, 0x8f, 0xed, 0x38, 0xb6, 0x57, 0x71, 0xe2, 0x5c, 0x92, 0x46, 0x71, 0x4b, 0xeb, 0x0a, 0x4a,
+	0x5d, 0x0a, 0x2e, 0xe3, 0x32, 0x40, 0xe9, 0xc0, 0x50, 0x92, 0xb6, 0x84, 0x69, 0x4b, 0x47, 0x49,
+	0xcb, 0x47, 0x8f, 0x22, 0x5d, 0x12, 0x81, 0xfe, 0x5c, 0x74, 0xe7, 0xb6, 0x79, 0x00, 0xde, 0x80,
+	0x47, 0xe0, 0x71, 0x78, 0x01, 0xde, 0x85, 0x0f, 0xcc, 0xee, 0x9d, 0x1c, 0x59, 0x76, 0x9b, 0xc2,
+	0x37, 0xed, 0x6f, 0x7f, 0xf7, 0x67, 0xf7, 0x76, 0x7f, 0x2b, 0x58, 0x16, 0x87, 0x77, 0x82, 0x44,
+	0x0e, 0x44, 0x96, 0xaa, 0x94, 0x55, 0xc5, 0x61, 0xf7, 0xda, 0x71, 0x9a, 0x1e, 0x47, 0xfc, 0x0e,
+	0x21, 0x87, 0xe3, 0xa3, 0x3b, 0x2a, 0x8c, 0xb9, 0x54, 0x5e, 0x2c, 0x34, 0xa9, 0x7b, 0xb5, 0x4c,
+	0x78, 0x9d, 0x79, 0x42, 0xf0, 0xcc, 0x6c, 0xe2, 0xfc, 0xd1, 0x00, 0xb6, 0xcf, 0xd5, 0xee, 0xb3,
+	0xfd, 0x9d, 0x13, 0x2f, 0x95, 0x2e, 0x3f, 0x1d, 0x73, 0xa9, 0x18, 0x83, 0xc5, 0xc4, 0x8b, 0xb9,
+	0x5d, 0xe9, 0x55, 0xfa, 0x2d, 0x97, 0xbe, 0xd9, 0x65, 0x58, 0x14, 0x69, 0x20, 0xed, 0x6a, 0xaf,
+	0xd6, 0xb7, 0x86, 0x8d, 0x81, 0x38, 0x1c, 0x3c, 0x4f, 0x03, 0x97, 0x40, 0x76, 0x09, 0x96, 0x3c,
+	0x5f, 0x85, 0x69, 0x62, 0xd7, 0x68, 0x89, 0xb1, 0xd8, 0x06, 0xd4, 0xa5, 0x9f, 0x0a, 0x6e, 0x2f,
+	0x12, 0xac, 0x0d, 0xd6, 0x85, 0xa6, 0xe4, 0x11, 0xf7, 0x55, 0x9a, 0xd9, 0x75, 0x72, 0x4c, 0x6c,
+	0xf4, 0x09, 0x4f, 0x29, 0x9e, 0x25, 0xd2, 0x5e, 0xea, 0xd5, 0xd0, 0x97, 0xdb, 0x6c, 0x0b, 0x1a,
+	0x41, 0x76, 0x36, 0xca, 0xc6, 0x89, 0xdd, 0xe8, 0x55, 0xfa, 0x4d, 0x77, 0x29, 0xc8, 0xce, 0xdc,
+	0x71, 0xc2, 0xae, 0x81, 0x95, 0x79, 0x49, 0x90, 0xc6, 0xa3, 0x38, 0x0d, 0xb8, 0xdd, 0xa4, 0x3d,
+	0x41, 0x43, 0x4f, 0xd3, 0x80, 0xb3, 0xeb, 0xb0, 0xec, 0x63, 0x14, 0x23, 0xe5, 0x65, 0xc7, 0x5c,
+	0xd9, 0x2d, 0x62, 0x58, 0x84, 0x1d, 0x10, 0x84, 0x7b, 0x68, 0x4a, 0xc0, 0x85, 0x3a, 0xb1, 0xa1,
+	0x57, 0xe9, 0xd7, 0x5d, 0x20, 0x68, 0x17, 0x11, 0x76, 0x0b, 0x3a, 0x2a, 0x1b, 0x27, 0xbe, 0xa7,
+	0xf8, 0x48, 0x78, 0x99, 0x0a, 0xbd, 0xc8, 0xb6, 0xe8, 0x1a, 0xab, 0x39, 0xfe, 0x5c, 0xc3, 0xec,
+	0x13, 0x58, 0x9b, 0x50, 0x95, 0x2f, 0x46, 0x47, 0x5e, 0x18, 0xd9, 0xcb, 0xd3, 0xdc, 0x03, 0x5f,
+	0x3c, 0xf2, 0xc2, 0x88, 0x02, 0xc6, 0xb7, 0xf0, 0xd3, 0xc8, 0x6e, 0xeb, 0x64, 0xe4, 0x36, 0xa6,
+	0xf5, 0x54, 0x9d, 0x09, 0x2e, 0xed, 0x15, 0x4a, 0x85, 0xb1, 0xd8, 0x6d, 0x58, 0x8b, 0xbd, 0xe8,
+	0x28, 0xcd, 0x62, 0x1e, 0x8c, 0x5e, 0x79, 0x59, 0xe8, 0x25, 0xca, 0x5e, 0xa5, 0xc5, 0x9d, 0x89,
+	0xe3, 0xa5, 0xc6, 0xd9, 0x0d, 0x58, 0x39, 0x27, 0x4b, 0xce, 0x03, 0xbb, 0xd3, 0xab, 0xf4, 0x6b,
+	0x6e, 0x7b, 0x82, 0xee, 0x73, 0x1e, 0x60, 0x8a, 0xe2, 0xb1, 0xc2, 0x1b, 0x63, 0x0e, 0xa5, 0xbd,
+	0x46, 0x27, 0x5a, 0x1a, 0xc3, 0x24, 0x4a, 0xf6, 0x01, 0x80, 0xa1, 0x28, 0x15, 0xd9, 0xac, 0x57,
+	0xe9, 0xb7, 0xdd, 0x96, 0x46, 0x0e, 0x54, 0x84, 0xee, 0x20, 0x4b, 0xc5, 0xc8, 0x4f, 0xc7, 0x89,
+	0xb2, 0xd7, 0x29, 0x81, 0x2d, 0x44, 0x76, 0x10, 0x60, 0x1f, 0x42, 0x9b, 0xdc, 0x47, 0x99, 0x29,
+	0x95, 0x8d, 0x5e, 0xa5, 0x5f, 0x71, 0x97, 0x11, 0x7c, 0x64, 0x30, 0xb6, 0x0d, 0x4d, 0x22, 0x85,
+	0x42, 0xda, 0x9b, 0x74, 0x83, 0x06, 0xda, 0x7b, 0x82, 0x4e, 0x3f, 0x49, 0x95, 0x14, 0xa9, 0x1a,
+	0x85, 0xc2, 0xbe, 0x44, 0xd1, 0xb6, 0x0c, 0xb2, 0x27, 0xd8, 0xf7, 0xd0, 0x8c, 0x43, 0x99, 0xa5,
+	0x63, 0xc5, 0xed, 0x2d, 0xaa, 0xd1, 0x8f, 0xb0, 0x46, 0x67, 0xab, 0x7b, 0xf0, 0xd4, 0xd0, 0x1e,
+	0x26, 0x2a, 0x3b, 0x73, 0x27, 0xab, 0x58, 0x07, 0x6a, 0x18, 0x97, 0x4d, 0x71, 0xe1, 0x27, 0xbb,
+	0x07, 0x2d, 0x1f, 0x57, 0x52, 0xbc, 0xdb, 0xbd, 0x4a, 0xdf, 0x1a, 0x5e, 0x19, 0xe8, 0x96, 0x1a,
+	0xe4, 0x2d, 0x35, 0x78, 0xb1, 0x97, 0xa8, 0xbb, 0xc3, 0x97, 0x5e, 0x34, 0xe6, 0x6e, 0x93, 0xe8,
+	0x07, 0x2a, 0xea, 0xde, 0x87, 0xf6, 0xd4, 0x39, 0xb8, 0xfb, 0x6f, 0xfc, 0xcc, 0xb4, 0x14, 0x7e,
+	0x62, 0x73, 0xbc, 0xc2, 0x55, 0x76, 0x55, 0x37, 0x07, 0x19, 0xdf, 0x54, 0xbf, 0xae, 0x38, 0x5f,
+	0x41, 0xed, 0x79, 0x1a, 0xb0, 0x2b, 0xd0, 0xc2, 0xf2, 0x93, 0xc2, 0xf3, 0xf3, 0x5e, 0x3c, 0x07,
+	0x26, 0x4d, 0x5a, 0x3d, 0x6f, 0x52, 0xe7, 0x36, 0x6c, 0xee, 0x78, 0x89, 0xcf, 0xa3, 0xf7, 0xe8,
+	0x68, 0xe7, 0xf7, 0x0a, 0x74, 0xce, 0x79, 0x52, 0xa4, 0x89, 0xe4, 0x58, 0x72, 0x19, 0x97, 0xe3,
+	0x48, 0x11, 0xb5, 0xe9, 0x1a, 0x0b, 0xaf, 0x1f, 0xcb, 0x63, 0x73, 0x18, 0x7e, 0xb2, 0xab, 0x00,
+	0xc7, 0x3c, 0xe1, 0x99, 0x37, 0xe9, 0xfb, 0x9a, 0x5b, 0x40, 0xd8, 0x4d, 0xa8, 0x4b, 0xe5, 0x29,
+	0x49, 0xbd, 0x6f, 0x0d, 0xd7, 0xf0, 0x35, 0xf2, 0xe3, 0xf6, 0xd1, 0xe1, 0x6a, 0xbf, 0xc3, 0x61,
+	0xf3, 0x85, 0x08, 0x3c, 0xc5, 0xcb, 0x97, 0xfe, 0x14, 0xea, 0x94, 0x4f, 0xba, 0x8a, 0x35, 0xbc,
+	0x34, 0xff, 0x3d, 0x5d, 0x4d, 0x2a, 0xdd, 0xa7, 0x5a, 0xbe, 0x8f, 0xb3, 0x09, 0xeb, 0x4f, 0x42,
+	0x59, 0x5e, 0xed, 0x9c, 0xc2, 0xc6, 0x34, 0x6c, 0x12, 0x31, 0x04, 0x8b, 0xbf, 0x11, 0x3c, 0x0b,
+	0x63, 0x9e, 0x28, 0xbc, 0x02, 0x96, 0x54, 0xa7, 0x18, 0xc4, 0x5e, 0x72, 0x94, 0xba, 0x45, 0x12,
+	0xbb, 0x01, 0x75, 0xe1, 0x8d, 0xa5, 0x7e, 0x13, 0x6b, 0xb8, 0x4a, 0x22, 0x89, 0x00, 0xc6, 0x3b,
+	0x96, 0xae, 0xf6, 0x3a, 0x7d, 0x60, 0x8f, 0xdf, 0x4b, 0x74, 0x9d, 0x7f, 0x2a, 0xb0, 0x5c, 0x3c,
+	0x8e, 0x7d, 0x09, 0x10, 0xf0, 0xa3, 0x30, 0x09, 0x29, 0xc8, 0x77, 0xe7, 0xa5, 0xc0, 0x64, 0xd7,
+	0xa7, 0xd4, 0xbb, 0x6d, 0xd4, 0xdb, 0x5c, 0x8b, 0x5c, 0xec, 0x3e, 0x58, 0x7e, 0xc6, 0xa9, 0xbb,
+	0xc3, 0x98, 0xd3, 0x83, 0x5a, 0xc3, 0xee, 0x4c, 0xb9, 0x1f, 0xe4, 0x23, 0xc6, 0x05, 0x4d, 0x47,
+	0x00, 0x2f, 0x7f, 0x12, 0x9a, 0xb7, 0xae, 0xb9, 0xf4, 0x5d, 0x7a, 0x90, 0xfa, 0x4c, 0x81, 0xf4,
+	0x60, 0xd9, 0xc8, 0xf9, 0x88, 0xd6, 0x2e, 0x69, 0x86, 0xd6, 0xf4, 0x1f, 0x43, 0x25, 0x9d, 0xa7,
+	0xd0, 0x9a, 0xdc, 0xf2, 0xbf, 0x77, 0x03, 0x5b, 0x81, 0x6a, 0x28, 0xcc, 0x44, 0xaa, 0x86, 0xc2,
+	0xf9, 0x0e, 0xba, 0xbf, 0x78, 0xca, 0x3f, 0xc9, 0x13, 0xf5, 0xf0, 0x15, 0xbe, 0x5a, 0x9e, 0xff,
+	0xde, 0xec, 0x83, 0xb7, 0xa6, 0x9e, 0xd7, 0xf9, 0xb3, 0x0a, 0xed, 0xa9, 0xb5, 0x6c, 0x00, 0x8b,
+	0x94, 0xac, 0xca, 0x85, 0xc9, 0x22, 0x1e, 0xa6, 0xe4, 0x7c, 0x43, 0x73, 0xd7, 0x02, 0xc2, 0xb6,
+	0xa1, 0x26, 0xd2, 0xc0, 0xe4, 0x7e, 0x32, 0x63, 0x11, 0x43, 0xb5, 0x38, 0xa5, 0x08, 0xcd, 0x28,
+	0x25, 0x83, 0x50, 0x9c, 0x09, 0x66, 0x8e, 0x6a, 0xa3, 0x30, 0x8e, 0x97, 0xca, 0xe3, 0x38, 0xf3,
+	0x71, 0x42, 0x36, 0x34, 0x9b, 0x0c, 0x66, 0x43, 0xc3, 0x4b, 0xe4, 0x6b, 0x9e, 0x49, 0xbb, 0xa9,
+	0x25, 0xd7, 0x98, 0xe8, 0x41, 0xf5, 0x15, 0x3c, 0xa0, 0x89, 0xb9, 0xe8, 0xe6, 0x26, 0x4e, 0xad,
+	0x80, 0xfb, 0xa1, 0xc4, 0x33, 0x40, 0x4f, 0xad, 0xdc, 0x76, 0x6e, 0xc1, 0x2a, 0x15, 0xfd, 0x83,
+	0x28, 0xca, 0x73, 0x4b, 0xaa, 0xe2, 0x49, 0x53, 0xb2, 0x2d, 0xd7, 0x58, 0x0e, 0x83, 0x8e, 0xcb,
+	0xe5, 0x38, 0x2e, 0x70, 0x9d, 0x37, 0x60, 0x15, 0x7a, 0x06, 0x97, 0x52, 0xd7, 0x04, 0xb9, 0x20,
+	0x69, 0xab, 0xb0, 0x65, 0xb5, 0xb8, 0x25, 0xbb, 0x07, 0x40, 0x8c, 0xf7, 0xad, 0xe2, 0x16, 0xb1,
+	0xd1, 0x76, 0x3e, 0x83, 0xad, 0x42, 0x5f, 0x6a, 0x8d, 0x7a, 0x47, 0x73, 0xfe, 0x5d, 0x83, 0xf6,
+	0x14, 0x79, 0x1e, 0x0b, 0x73, 0x18, 0x63, 0xd1, 0xf1, 0xc0, 0x68, 0x52, 0x6e, 0x62, 0x0e, 0xc3,
+	0xe4, 0x57, 0xee, 0x2b, 0x1e, 0x18, 0xf9, 0x9c, 0xd8, 0xec, 0x05, 0xb0, 0xfc, 0x7b, 0x74, 0x78,
+	0x36, 0x32, 0xaf, 0xb9, 0x48, 0xdd, 0x7b, 0x73, 0x46, 0x49, 0x07, 0x7b, 0x86, 0xfb, 0xc3, 0xd9,
+	0x03, 0x62, 0xea, 0xd1, 0xd6, 0x09, 0x4b, 0xf0, 0x4c, 0xcb, 0xd5, 0xcb, 0x2d, 0xc7, 0x3e, 0x86,
+	0xa6, 0x48, 0x83, 0xbc, 0x21, 0xf1, 0x38, 0xcb, 0x94, 0x21, 0xba, 0xdd, 0x86, 0xd0, 0x1f, 0xec,
+	0x09, 0x6c, 0x1c, 0x85, 0x99, 0x54, 0x23, 0x7d, 0x46, 0x98, 0x26, 0x3a, 0xe1, 0x8d, 0x0b, 0x13,
+	0xce, 0x68, 0xdd, 0x5e, 0xbe, 0x0c, 0x1d, 0xec, 0x27, 0x58, 0x8f, 0xbc, 0xd9, 0xcd, 0x9a, 0x17,
+	0x6e, 0xb6, 0x16, 0x79, 0xa5, 0xbd, 0xba, 0x3b, 0xb0, 0x39, 0x37, 0x1d, 0x17, 0x4d, 0xe0, 0x5a,
+	0x71, 0x02, 0xff, 0x0c, 0x0d, 0x13, 0xf2, 0xff, 0xd0, 0x9d, 0x5c, 0x0c, 0x6b, 0xe7, 0x62, 0x38,
+	0xfc, 0x6b, 0x11, 0x6a, 0xbb, 0xcf, 0xf6, 0xd9, 0xb7, 0x60, 0x15, 0xa4, 0x9a, 0xbd, 0x45, 0xbb,
+	0xbb, 0x1b, 0xc5, 0x37, 0xce, 0x67, 0x92, 0xb3, 0xc0, 0x76, 0x60, 0x65, 0x7a, 0xc0, 0xb3, 0x6d,
+	0x64, 0xce, 0x1d, 0xfa, 0xef, 0xd8, 0x64, 0xb9, 0x38, 0xf2, 0xd8, 0x16, 0xf2, 0xe6, 0xcc, 0xc6,
+	0xae, 0x3d, 0xeb, 0x98, 0x6c, 0x72, 0x0f, 0xac, 0xc7, 0xe5, 0x40, 0x66, 0xa7, 0x5a, 0x77, 0x66,
+	0x62, 0xea, 0x20, 0xa6, 0x07, 0xbe, 0x0e, 0x62, 0xee, 0x4f, 0xc0, 0x5b, 0x83, 0x78, 0x02, 0xeb,
+	0x73, 0xc4, 0x9c, 0x5d, 0x45, 0xfa, 0xdb, 0x55, 0xbe, 0x3b, 0xf5, 0x1b, 0x42, 0x2e, 0x67, 0xe1,
+	0xf3, 0x0a, 0x1b, 0x42, 0x33, 0xd7, 0x2c, 0xb6, 0x3e, 0x19, 0xdb, 0xe7, 0xaa, 0xd4, 0x2d, 0xcf,
+	0x72, 0x67, 0x81, 0x7d, 0x01, 0xad, 0x89, 0x78, 0x31, 0xba, 0x66, 0x59, 0xcb, 0xe6, 0xad, 0xda,
+	0x85, 0x4e, 0x59, 0x64, 0xd8, 0xe5, 0x52, 0xf2, 0x8a, 0xd2, 0xd3, 0x9d, 0xfd, 0x71, 0x72, 0x16,
+	0x0e, 0x97, 0xa8, 0x17, 0xee, 0xfe, 0x3b, 0x00, 0x28, 0x2b, 0xf8, 0xf3, 0x14, 0x0e, 0x00, 0x00,
 }
@@ -0,0 +1,214 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// killSwitch is the content of the kill switch file, chaos is paused as long as the file exists
+type killSwitch struct {
+	Reason string    `json:"reason"`
+	Time   time.Time `json:"time"`
+}
+
+// isPaused returns whether chaos is paused by PauseAll, it is called without the lock
+func (k *Kubernetes) isPaused() bool {
+	return atomic.LoadInt32(&k.paused) == 1
+}
+
+// setPaused changes the pause state, the caller should hold the lock
+func (k *Kubernetes) setPaused(paused bool, reason string, pauseTime time.Time) {
+	if paused {
+		atomic.StoreInt32(&k.paused, 1)
+		chaosPaused.Set(1)
+	} else {
+		atomic.StoreInt32(&k.paused, 0)
+		chaosPaused.Set(0)
+	}
+	k.pauseReason = reason
+	k.pauseTime = pauseTime
+}
+
+// pauseStatus returns the pause state, the caller should hold the lock
+func (k *Kubernetes) pauseStatus() *pb.PauseStatus {
+	s := &pb.PauseStatus{Paused: k.isPaused()}
+	if s.Paused {
+		s.Reason = k.pauseReason
+		s.PauseTime, _ = ptypes.TimestampProto(k.pauseTime)
+	}
+	return s
+}
+
+// loadKillSwitch pauses chaos if the kill switch file exists, and resumes it otherwise,
+// so that the kill switch survives restarts and can be changed by hand before a reload
+func (k *Kubernetes) loadKillSwitch() error {
+	if k.killSwitchFile == "" {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(k.killSwitchFile)
+	if os.IsNotExist(err) {
+		k.Lock()
+		k.setPaused(false, "", time.Time{})
+		k.Unlock()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fail to read kill switch: %v", err)
+	}
+
+	// the file may be created by hand, so its content is optional
+	s := killSwitch{Reason: "kill switch file exists"}
+	if len(content) != 0 {
+		if err := json.Unmarshal(content, &s); err != nil {
+			log.Warningf("invalid kill switch %s: %v", k.killSwitchFile, err)
+		}
+	}
+
+	k.Lock()
+	k.setPaused(true, s.Reason, s.Time)
+	k.Unlock()
+	log.Warningf("chaos is paused by kill switch %s: %s", k.killSwitchFile, s.Reason)
+	return nil
+}
+
+// saveKillSwitch writes the kill switch file through a temporary file, so that it is never half written
+func (k *Kubernetes) saveKillSwitch(s killSwitch) error {
+	content, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(k.killSwitchFile), filepath.Base(k.killSwitchFile)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), k.killSwitchFile)
+}
+
+// PauseAll stops injecting chaos of all the experiments, including the chaos configured in Corefile.
+// The experiments are kept, and chaos is injected again after ResumeAll. If chaos is already paused,
+// the pause time is kept and the reason is replaced by the reason of the request unless it is empty.
+func (k *Kubernetes) PauseAll(ctx context.Context, req *pb.PauseAllRequest) (_ *pb.PauseStatus, err error) {
+	log.Warningf("receive PauseAll request %v", req)
+	defer func() { k.audit.control(ctx, "PauseAll", "", req, err) }()
+
+	k.Lock()
+	defer k.Unlock()
+
+	switch {
+	case !k.isPaused():
+		// stop injecting before anything else
+		k.setPaused(true, req.Reason, time.Now())
+	case req.Reason != "":
+		k.setPaused(true, req.Reason, k.pauseTime)
+	}
+
+	if k.killSwitchFile != "" {
+		if err := k.saveKillSwitch(killSwitch{Reason: k.pauseReason, Time: k.pauseTime}); err != nil {
+			log.Errorf("fail to save kill switch: %v", err)
+			return nil, status.Errorf(codes.Internal, "chaos is paused, but the kill switch fails to be saved: %v", err)
+		}
+	}
+
+	return k.pauseStatus(), nil
+}
+
+// ResumeAll injects chaos of the experiments again after PauseAll.
+func (k *Kubernetes) ResumeAll(ctx context.Context, req *pb.ResumeAllRequest) (_ *pb.PauseStatus, err error) {
+	log.Warningf("receive ResumeAll request %v", req)
+	defer func() { k.audit.control(ctx, "ResumeAll", "", req, err) }()
+
+	k.Lock()
+	defer k.Unlock()
+
+	if k.killSwitchFile != "" {
+		// keep chaos paused if the kill switch can't be removed, otherwise it is paused again after a restart
+		if err := os.Remove(k.killSwitchFile); err != nil && !os.IsNotExist(err) {
+			log.Errorf("fail to remove kill switch: %v", err)
+			return nil, status.Errorf(codes.Internal, "fail to remove the kill switch: %v", err)
+		}
+	}
+	k.setPaused(false, "", time.Time{})
+
+	return k.pauseStatus(), nil
+}
+
+// checkBlastRadius checks the limits of the count of pods targeted by all the experiments and the
+// percentage of pods targeted in a namespace, as if the request is applied to the experiment.
+// namespaceSizes are the counts of pods in the namespaces of the request, listed by resolveRequestPods.
+// The caller should hold the lock.
+func (k *Kubernetes) checkBlastRadius(experiment *Experiment, req *pb.SetDNSChaosRequest, namespaceSizes map[string]int) error {
+	if k.maxChaosPods == 0 && k.maxChaosNamespacePercent == 0 {
+		return nil
+	}
+
+	// namespace -> pod name, the pods of the experiment are replaced by the pods of the request.
+	// The experiment is compared by name, because SetDNSChaos replaces the experiment with the same name.
+	targeted := make(map[string]map[string]struct{})
+	target := func(namespace, name string) {
+		if _, ok := targeted[namespace]; !ok {
+			targeted[namespace] = make(map[string]struct{})
+		}
+		targeted[namespace][name] = struct{}{}
+	}
+	for namespace, pods := range k.podMap {
+		for name, podInfo := range pods {
+			if podInfo.Experiment == nil || podInfo.Experiment.Name != experiment.Name {
+				target(namespace, name)
+			}
+		}
+	}
+	for _, pod := range req.Pods {
+		target(pod.Namespace, pod.Name)
+	}
+
+	if k.maxChaosPods != 0 {
+		count := 0
+		for _, pods := range targeted {
+			count += len(pods)
+		}
+		if count > k.maxChaosPods {
+			return status.Errorf(codes.ResourceExhausted, "%d pods would be targeted by chaos, more than the limit %d", count, k.maxChaosPods)
+		}
+	}
+
+	if k.maxChaosNamespacePercent != 0 {
+		checked := make(map[string]struct{})
+		for _, pod := range req.Pods {
+			if _, ok := checked[pod.Namespace]; ok {
+				continue
+			}
+			checked[pod.Namespace] = struct{}{}
+
+			count, size := len(targeted[pod.Namespace]), namespaceSizes[pod.Namespace]
+			if size == 0 || count*100 > k.maxChaosNamespacePercent*size {
+				return status.Errorf(codes.ResourceExhausted, "%d of %d pods in namespace %s would be targeted by chaos, more than the limit %d%%",
+					count, size, pod.Namespace, k.maxChaosNamespacePercent)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package kubernetes
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/miekg/dns"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPauseAll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "k8s_dns_chaos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	killSwitchFile := filepath.Join(dir, "kill_switch")
+
+	ctx := context.TODO()
+	k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionError})
+	k.killSwitchFile = killSwitchFile
+	experiment := k.chaosMap["chaos"]
+
+	serve := func() int {
+		m := new(dns.Msg)
+		m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+		w := dnstest.NewRecorder(&test.ResponseWriter{})
+		rcode, _ := k.ServeDNS(ctx, w, m)
+		if w.Msg != nil {
+			rcode = w.Msg.Rcode
+		}
+		return rcode
+	}
+
+	s, err := k.PauseAll(ctx, &pb.PauseAllRequest{Reason: "incident"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !s.Paused || s.Reason != "incident" || s.PauseTime == nil {
+		t.Errorf("Expected paused by incident, got %v", s)
+	}
+	if rcode := serve(); rcode != dns.RcodeSuccess {
+		t.Errorf("Expected rcode %d while paused, got %d", dns.RcodeSuccess, rcode)
+	}
+	if experiment.HitCount() != 0 {
+		t.Errorf("Expected no hit while paused, got %d", experiment.HitCount())
+	}
+	if _, ok := k.chaosMap["chaos"]; !ok {
+		t.Errorf("Expected the experiment to be kept while paused")
+	}
+	if _, err := os.Stat(killSwitchFile); err != nil {
+		t.Errorf("Expected the kill switch to be saved, got %v", err)
+	}
+
+	// pausing again replaces the reason but keeps the pause time
+	pauseTime := s.PauseTime
+	s, err = k.PauseAll(ctx, &pb.PauseAllRequest{Reason: "incident-2"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !s.Paused || s.Reason != "incident-2" || !proto.Equal(s.PauseTime, pauseTime) {
+		t.Errorf("Expected paused by incident-2 since %v, got %v", pauseTime, s)
+	}
+	if s, _ = k.PauseAll(ctx, &pb.PauseAllRequest{}); s.Reason != "incident-2" {
+		t.Errorf("Expected the reason to be kept without a new one, got %v", s)
+	}
+
+	// the kill switch pauses the new instance after a restart
+	restarted := New([]string{"cluster.local."})
+	restarted.killSwitchFile = killSwitchFile
+	if err := restarted.loadKillSwitch(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if s := restarted.pauseStatus(); !s.Paused || s.Reason != "incident-2" {
+		t.Errorf("Expected paused by incident-2 after restart, got %v", s)
+	}
+
+	s, err = k.ResumeAll(ctx, &pb.ResumeAllRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if s.Paused {
+		t.Errorf("Expected resumed, got %v", s)
+	}
+	if rcode := serve(); rcode != dns.RcodeServerFailure {
+		t.Errorf("Expected rcode %d after resumed, got %d", dns.RcodeServerFailure, rcode)
+	}
+	if _, err := os.Stat(killSwitchFile); !os.IsNotExist(err) {
+		t.Errorf("Expected the kill switch to be removed, got %v", err)
+	}
+
+	// a kill switch created by hand has no content
+	if err := ioutil.WriteFile(killSwitchFile, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.loadKillSwitch(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !k.isPaused() {
+		t.Errorf("Expected paused by the kill switch created by hand")
+	}
+}
+
+func TestBlastRadius(t *testing.T) {
+	ctx := context.Background()
+	k := newGRPCTestKubernetes(
+		testPod("testns", "busybox-0", "10.0.0.1"),
+		testPod("testns", "busybox-1", "10.0.0.2"),
+		testPod("testns", "busybox-2", "10.0.0.3"),
+		testPod("testns", "busybox-3", "10.0.0.4"),
+		testPod("otherns", "busybox-0", "10.0.1.1"),
+	)
+	k.maxChaosPods = 3
+	k.maxChaosNamespacePercent = 50
+
+	tests := []struct {
+		req          *pb.SetDNSChaosRequest
+		expectedCode codes.Code
+	}{
+		{&pb.SetDNSChaosRequest{Name: "a", Action: ActionError, Pods: []*pb.Pod{{Namespace: "testns", Name: "busybox-0"}}}, codes.OK},
+		// 3 of 4 pods in testns
+		{&pb.SetDNSChaosRequest{Name: "b", Action: ActionError, Pods: []*pb.Pod{{Namespace: "testns", Name: "busybox-1"}, {Namespace: "testns", Name: "busybox-2"}}}, codes.ResourceExhausted},
+		{&pb.SetDNSChaosRequest{Name: "b", Action: ActionError, Pods: []*pb.Pod{{Namespace: "testns", Name: "busybox-1"}}}, codes.OK},
+		// the pods of the experiment itself are replaced
+		{&pb.SetDNSChaosRequest{Name: "b", Action: ActionError, Pods: []*pb.Pod{{Namespace: "testns", Name: "busybox-2"}}}, codes.OK},
+		// 1 of 1 pod in otherns
+		{&pb.SetDNSChaosRequest{Name: "c", Action: ActionError, Pods: []*pb.Pod{{Namespace: "otherns", Name: "busybox-0"}}}, codes.ResourceExhausted},
+	}
+
+	for i, tc := range tests {
+		_, err := k.SetDNSChaos(ctx, tc.req)
+		if status.Code(err) != tc.expectedCode {
+			t.Errorf("Test %d: Expected %v, got %v", i, tc.expectedCode, err)
+		}
+	}
+
+	// 4 pods in total
+	k.maxChaosNamespacePercent = 100
+	_, err := k.SetDNSChaos(ctx, &pb.SetDNSChaosRequest{Name: "c", Action: ActionError, Pods: []*pb.Pod{{Namespace: "testns", Name: "busybox-1"}, {Namespace: "testns", Name: "busybox-3"}}})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted for the count of pods, got %v", err)
+	}
+}
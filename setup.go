@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/coredns/coredns/core/dnsserver"
 	"github.com/coredns/coredns/plugin"
+	"github.com/coredns/coredns/plugin/metrics"
 	"github.com/coredns/coredns/plugin/pkg/dnsutil"
 	clog "github.com/coredns/coredns/plugin/pkg/log"
 	"github.com/coredns/coredns/plugin/pkg/parse"
@@ -26,7 +28,12 @@ import (
 	"k8s.io/klog"
 )
 
-const pluginName = "k8s_dns_chaos"
+const (
+	pluginName = "k8s_dns_chaos"
+
+	// unixSocketPrefix is the prefix of grpc_address for listening on a unix socket
+	unixSocketPrefix = "unix://"
+)
 
 var log = clog.NewWithPlugin(pluginName)
 
@@ -43,11 +50,21 @@ func setup(c *caddy.Controller) error {
 		return plugin.Error(pluginName, err)
 	}
 
+	k.takeOverChaosState()
+
 	err = k.InitKubeCache(context.Background())
 	if err != nil {
 		return plugin.Error(pluginName, err)
 	}
 
+	// the state of the instance being reloaded is only changed after the setup succeeded, it is
+	// taken over before anything else uses the state on startup. If the startup fails after it,
+	// the instance being reloaded rolls the changes back in OnRestartFailed.
+	c.OnStartup(func() error {
+		k.startChaosState()
+		return k.loadKillSwitch()
+	})
+
 	k.RegisterKubeCache(c)
 
 	dnsserver.GetConfig(c).AddPlugin(func(next plugin.Handler) plugin.Handler {
@@ -61,11 +78,25 @@ func setup(c *caddy.Controller) error {
 		return nil
 	})
 
-	err = k.CreateGRPCServer()
-	if err != nil {
-		return plugin.Error(pluginName, err)
+	if k.audit != nil {
+		// the audit log is opened before the grpc server starts, so that no change is missed
+		c.OnStartup(k.audit.open)
+		c.OnShutdown(k.audit.close)
+	}
+
+	if k.tap != nil {
+		c.OnStartup(k.tap.open)
+		c.OnShutdown(k.tap.close)
 	}
 
+	k.RegisterGRPCServer(c)
+
+	c.OnStartup(func() error {
+		metrics.MustRegister(c, DnsProgrammingLatency, chaosMatched, chaosInjected, chaosPassedThrough,
+			podResolutionFailures, activeExperiments, chaosPaused, grpcRequests, grpcRequestDuration)
+		return nil
+	})
+
 	return nil
 }
 
@@ -91,6 +122,17 @@ func (k *Kubernetes) RegisterKubeCache(c *caddy.Controller) {
 	c.OnShutdown(func() error {
 		return k.APIConn.Stop()
 	})
+
+	if k.crdWatcher != nil {
+		c.OnStartup(func() error {
+			go k.crdWatcher.Run()
+			return nil
+		})
+
+		c.OnShutdown(func() error {
+			return k.crdWatcher.Stop()
+		})
+	}
 }
 
 func kubernetesParse(c *caddy.Controller) (*Kubernetes, error) {
@@ -288,14 +330,133 @@ func ParseStanza(c *caddy.Controller) (*Kubernetes, error) {
 				}
 				k8s.grpcPort = port
 			}
+		case "grpc_address":
+			args := c.RemainingArgs()
+			if len(args) != 1 {
+				return nil, c.ArgErr()
+			}
+			if !strings.HasPrefix(args[0], unixSocketPrefix) {
+				if _, _, err := net.SplitHostPort(args[0]); err != nil {
+					return nil, c.Errf("grpc_address must be [HOST]:PORT or %sPATH: %v", unixSocketPrefix, err)
+				}
+			}
+			k8s.grpcAddress = args[0]
+		case "grpc_health_address":
+			args := c.RemainingArgs()
+			if len(args) != 1 {
+				return nil, c.ArgErr()
+			}
+			_, port, err := net.SplitHostPort(args[0])
+			if err == nil {
+				_, err = strconv.ParseUint(port, 10, 16)
+			}
+			if err != nil {
+				return nil, c.Errf("grpc_health_address must be [HOST]:PORT: %v", err)
+			}
+			k8s.grpcHealthAddress = args[0]
+		case "grpc_tls": // cert key [clientcafile]
+			args := c.RemainingArgs()
+			if len(args) != 2 && len(args) != 3 {
+				return nil, c.ArgErr()
+			}
+			k8s.grpcTLSCert, k8s.grpcTLSKey = args[0], args[1]
+			if len(args) == 3 {
+				k8s.grpcTLSClientCA = args[2]
+			}
+		case "grpc_token":
+			args := c.RemainingArgs()
+			if len(args) != 1 {
+				return nil, c.ArgErr()
+			}
+			k8s.grpcTokenFile = args[0]
+		case "audit_log":
+			args := c.RemainingArgs()
+			if len(args) == 0 || len(args) > 2 {
+				return nil, c.ArgErr()
+			}
+			sampleRate := defaultAuditSampleRate
+			if len(args) == 2 {
+				rate, err := strconv.ParseFloat(args[1], 64)
+				if err != nil || rate < 0 || rate > 1 {
+					return nil, c.Errf("audit_log sample rate must be between 0 and 1, got '%s'", args[1])
+				}
+				sampleRate = rate
+			}
+			k8s.audit = newAuditLogger(args[0], sampleRate)
+		case "chaos_dnstap":
+			args := c.RemainingArgs()
+			if len(args) != 1 {
+				return nil, c.ArgErr()
+			}
+			k8s.tap = newChaosTap(args[0])
+		case "kill_switch":
+			args := c.RemainingArgs()
+			if len(args) != 1 {
+				return nil, c.ArgErr()
+			}
+			k8s.killSwitchFile = args[0]
+		case "max_chaos_pods":
+			args := c.RemainingArgs()
+			if len(args) != 1 {
+				return nil, c.ArgErr()
+			}
+			count, err := strconv.Atoi(args[0])
+			if err != nil || count <= 0 {
+				return nil, c.Errf("max_chaos_pods must be a positive integer, got '%s'", args[0])
+			}
+			k8s.maxChaosPods = count
+		case "max_chaos_namespace_percent":
+			args := c.RemainingArgs()
+			if len(args) != 1 {
+				return nil, c.ArgErr()
+			}
+			percent, err := strconv.Atoi(args[0])
+			if err != nil || percent <= 0 || percent > 100 {
+				return nil, c.Errf("max_chaos_namespace_percent must be between 1 and 100, got '%s'", args[0])
+			}
+			k8s.maxChaosNamespacePercent = percent
+		case "chaos_crd":
+			args := c.RemainingArgs()
+			if len(args) > 1 {
+				return nil, c.ArgErr()
+			}
+			k8s.chaosCRD = true
+			if len(args) == 1 {
+				k8s.chaosCRDNamespace = args[0]
+			}
 		case "chaos":
 			/*
 				the sample config:
 					chaos error outer busybox.busybox-0 busybox.busybox-1
 					chaos random inner busybox.busybox-2 busybox.busybox-3
+					chaos error all busybox.busybox-4 qtypes AAAA
 			*/
 			args := c.RemainingArgs()
+			var qtypes []uint16
+			for i, arg := range args {
+				if arg != "qtypes" {
+					continue
+				}
+				var err error
+				if qtypes, err = parseQTypes(args[i+1:]); err != nil {
+					return nil, c.Err(err.Error())
+				}
+				if len(qtypes) == 0 {
+					return nil, c.ArgErr()
+				}
+				args = args[:i]
+				break
+			}
 			if len(args) >= 3 {
+				if !isValidAction(args[0]) {
+					return nil, c.Errf("unknown chaos action '%s'", args[0])
+				}
+				if args[0] == ActionCNAME || args[0] == ActionMisroute {
+					return nil, c.Errf("chaos action '%s' needs targets, it can only be set through the GRPC service", args[0])
+				}
+				if !isValidScope(args[1]) {
+					return nil, c.Errf("unknown chaos scope '%s'", args[1])
+				}
 				for i := 2; i < len(args); i++ {
 					items := strings.SplitN(args[i], ".", 2)
 					if len(items) != 2 {
@@ -310,6 +471,7 @@ func ParseStanza(c *caddy.Controller) (*Kubernetes, error) {
 						Name:      items[1],
 						Action:    args[0],
 						Scope:     args[1],
+						QTypes:    qtypes,
 					}
 				}
 			} else {
@@ -325,6 +487,10 @@ func ParseStanza(c *caddy.Controller) (*Kubernetes, error) {
 		return nil, c.Errf("namespaces and namespace_labels cannot both be set")
 	}
 
+	if k8s.grpcPort != 0 && k8s.grpcAddress != "" {
+		return nil, c.Errf("grpcport and grpc_address cannot both be set")
+	}
+
 	return k8s, nil
 }
 
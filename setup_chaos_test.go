@@ -0,0 +1,351 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/caddyserver/caddy"
+	"github.com/miekg/dns"
+)
+
+func TestKubernetesParseChaos(t *testing.T) {
+	tests := []struct {
+		input     string // Corefile data as string
+		shouldErr bool
+	}{
+		{`kubernetes cluster.local {
+			chaos error all busybox.busybox-0
+		}`, false},
+		{`kubernetes cluster.local {
+			chaos delay all busybox.busybox-0
+		}`, true},
+		{`kubernetes cluster.local {
+			chaos error cluster busybox.busybox-0
+		}`, true},
+		{`kubernetes cluster.local {
+			chaos error all busybox
+		}`, true},
+		{`kubernetes cluster.local {
+			chaos cname_chain all busybox.busybox-0
+		}`, false},
+		// the target of cname can't be set in Corefile
+		{`kubernetes cluster.local {
+			chaos cname all busybox.busybox-0
+		}`, true},
+		// neither can the targets of misroute
+		{`kubernetes cluster.local {
+			chaos misroute all busybox.busybox-0
+		}`, true},
+		{`kubernetes cluster.local {
+			chaos error all busybox.busybox-0 qtypes
+		}`, true},
+		{`kubernetes cluster.local {
+			chaos error all busybox.busybox-0 qtypes AAAA BOGUS
+		}`, true},
+		{`kubernetes cluster.local {
+			chaos error all qtypes AAAA
+		}`, true},
+	}
+
+	for i, tc := range tests {
+		c := caddy.NewTestController("dns", tc.input)
+		_, err := kubernetesParse(c)
+		if err != nil && !tc.shouldErr {
+			t.Errorf("Test %d: Expected no error, got %q", i, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Errorf("Test %d: Expected error, got none", i)
+		}
+	}
+}
+
+func TestKubernetesParseChaosQTypes(t *testing.T) {
+	c := caddy.NewTestController("dns", `kubernetes cluster.local {
+		chaos error all busybox.busybox-0 busybox.busybox-1 qtypes aaaa SRV
+	}`)
+	k, err := kubernetesParse(c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %q", err)
+	}
+
+	for _, name := range []string{"busybox-0", "busybox-1"} {
+		qtypes := k.podMap["busybox"][name].QTypes
+		if len(qtypes) != 2 || qtypes[0] != dns.TypeAAAA || qtypes[1] != dns.TypeSRV {
+			t.Errorf("Expected qtypes AAAA and SRV of pod %s, got %v", name, qtypes)
+		}
+	}
+}
+
+func TestKubernetesParseChaosCRD(t *testing.T) {
+	tests := []struct {
+		input             string // Corefile data as string
+		expectedCRD       bool
+		expectedNamespace string
+		shouldErr         bool
+	}{
+		{`kubernetes cluster.local`, false, "", false},
+		{`kubernetes cluster.local {
+			chaos_crd
+		}`, true, "", false},
+		{`kubernetes cluster.local {
+			chaos_crd chaos-testing
+		}`, true, "chaos-testing", false},
+		{`kubernetes cluster.local {
+			chaos_crd chaos-testing default
+		}`, false, "", true},
+	}
+
+	for i, tc := range tests {
+		c := caddy.NewTestController("dns", tc.input)
+		k, err := kubernetesParse(c)
+		if err != nil && !tc.shouldErr {
+			t.Fatalf("Test %d: Expected no error, got %q", i, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Fatalf("Test %d: Expected error, got none", i)
+		}
+		if err != nil && tc.shouldErr {
+			// input should error
+			continue
+		}
+
+		if k.chaosCRD != tc.expectedCRD {
+			t.Errorf("Test %d: Expected chaos_crd to be %v, got %v", i, tc.expectedCRD, k.chaosCRD)
+		}
+		if k.chaosCRDNamespace != tc.expectedNamespace {
+			t.Errorf("Test %d: Expected chaos_crd namespace %q, got %q", i, tc.expectedNamespace, k.chaosCRDNamespace)
+		}
+	}
+}
+
+func TestKubernetesParseAuditLog(t *testing.T) {
+	tests := []struct {
+		input              string // Corefile data as string
+		expectedOutput     string
+		expectedSampleRate float64
+		shouldErr          bool
+	}{
+		{`kubernetes cluster.local {
+			audit_log stdout
+		}`, "stdout", defaultAuditSampleRate, false},
+		{`kubernetes cluster.local {
+			audit_log /var/log/chaos-audit.log 1
+		}`, "/var/log/chaos-audit.log", 1, false},
+		{`kubernetes cluster.local {
+			audit_log stdout 2
+		}`, "", 0, true},
+		{`kubernetes cluster.local {
+			audit_log
+		}`, "", 0, true},
+	}
+
+	for i, tc := range tests {
+		c := caddy.NewTestController("dns", tc.input)
+		k, err := kubernetesParse(c)
+		if err != nil && !tc.shouldErr {
+			t.Fatalf("Test %d: Expected no error, got %q", i, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Fatalf("Test %d: Expected error, got none", i)
+		}
+		if err != nil && tc.shouldErr {
+			// input should error
+			continue
+		}
+
+		if k.audit.output != tc.expectedOutput || k.audit.sampleRate != tc.expectedSampleRate {
+			t.Errorf("Test %d: Expected audit_log %s %v, got %s %v", i, tc.expectedOutput, tc.expectedSampleRate, k.audit.output, k.audit.sampleRate)
+		}
+	}
+}
+
+func TestKubernetesParseChaosDnstap(t *testing.T) {
+	tests := []struct {
+		input            string // Corefile data as string
+		expectedEndpoint string
+		shouldErr        bool
+	}{
+		{`kubernetes cluster.local {
+			chaos_dnstap unix:///var/run/chaos-tap.sock
+		}`, "unix:///var/run/chaos-tap.sock", false},
+		{`kubernetes cluster.local {
+			chaos_dnstap /var/log/chaos.dnstap
+		}`, "/var/log/chaos.dnstap", false},
+		{`kubernetes cluster.local {
+			chaos_dnstap
+		}`, "", true},
+	}
+
+	for i, tc := range tests {
+		c := caddy.NewTestController("dns", tc.input)
+		k, err := kubernetesParse(c)
+		if err != nil && !tc.shouldErr {
+			t.Fatalf("Test %d: Expected no error, got %q", i, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Fatalf("Test %d: Expected error, got none", i)
+		}
+		if err != nil && tc.shouldErr {
+			// input should error
+			continue
+		}
+
+		if k.tap.endpoint != tc.expectedEndpoint {
+			t.Errorf("Test %d: Expected chaos_dnstap %s, got %s", i, tc.expectedEndpoint, k.tap.endpoint)
+		}
+	}
+}
+
+func TestKubernetesParseSafety(t *testing.T) {
+	tests := []struct {
+		input                    string // Corefile data as string
+		expectedKillSwitch       string
+		expectedMaxPods          int
+		expectedMaxNamespacePods int
+		shouldErr                bool
+	}{
+		{`kubernetes cluster.local {
+			kill_switch /var/lib/coredns/kill_switch
+			max_chaos_pods 10
+			max_chaos_namespace_percent 30
+		}`, "/var/lib/coredns/kill_switch", 10, 30, false},
+		{`kubernetes cluster.local`, "", 0, 0, false},
+		{`kubernetes cluster.local {
+			kill_switch
+		}`, "", 0, 0, true},
+		{`kubernetes cluster.local {
+			max_chaos_pods 0
+		}`, "", 0, 0, true},
+		{`kubernetes cluster.local {
+			max_chaos_pods ten
+		}`, "", 0, 0, true},
+		{`kubernetes cluster.local {
+			max_chaos_namespace_percent 101
+		}`, "", 0, 0, true},
+	}
+
+	for i, tc := range tests {
+		c := caddy.NewTestController("dns", tc.input)
+		k, err := kubernetesParse(c)
+		if err != nil && !tc.shouldErr {
+			t.Fatalf("Test %d: Expected no error, got %q", i, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Fatalf("Test %d: Expected error, got none", i)
+		}
+		if err != nil && tc.shouldErr {
+			// input should error
+			continue
+		}
+
+		if k.killSwitchFile != tc.expectedKillSwitch {
+			t.Errorf("Test %d: Expected kill_switch %s, got %s", i, tc.expectedKillSwitch, k.killSwitchFile)
+		}
+		if k.maxChaosPods != tc.expectedMaxPods {
+			t.Errorf("Test %d: Expected max_chaos_pods %d, got %d", i, tc.expectedMaxPods, k.maxChaosPods)
+		}
+		if k.maxChaosNamespacePercent != tc.expectedMaxNamespacePods {
+			t.Errorf("Test %d: Expected max_chaos_namespace_percent %d, got %d", i, tc.expectedMaxNamespacePods, k.maxChaosNamespacePercent)
+		}
+	}
+}
+
+func TestKubernetesParseGRPCAuth(t *testing.T) {
+	tests := []struct {
+		input            string // Corefile data as string
+		expectedCert     string
+		expectedKey      string
+		expectedClientCA string
+		expectedToken    string
+		shouldErr        bool
+	}{
+		{`kubernetes cluster.local {
+			grpc_tls server.crt server.key
+		}`, "server.crt", "server.key", "", "", false},
+		{`kubernetes cluster.local {
+			grpc_tls server.crt server.key ca.crt
+			grpc_token /var/run/secrets/token
+		}`, "server.crt", "server.key", "ca.crt", "/var/run/secrets/token", false},
+		{`kubernetes cluster.local {
+			grpc_tls server.crt
+		}`, "", "", "", "", true},
+		{`kubernetes cluster.local {
+			grpc_token
+		}`, "", "", "", "", true},
+	}
+
+	for i, tc := range tests {
+		c := caddy.NewTestController("dns", tc.input)
+		k, err := kubernetesParse(c)
+		if err != nil && !tc.shouldErr {
+			t.Fatalf("Test %d: Expected no error, got %q", i, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Fatalf("Test %d: Expected error, got none", i)
+		}
+		if err != nil && tc.shouldErr {
+			// input should error
+			continue
+		}
+
+		if k.grpcTLSCert != tc.expectedCert || k.grpcTLSKey != tc.expectedKey || k.grpcTLSClientCA != tc.expectedClientCA {
+			t.Errorf("Test %d: Expected grpc_tls %q %q %q, got %q %q %q", i, tc.expectedCert, tc.expectedKey, tc.expectedClientCA,
+				k.grpcTLSCert, k.grpcTLSKey, k.grpcTLSClientCA)
+		}
+		if k.grpcTokenFile != tc.expectedToken {
+			t.Errorf("Test %d: Expected grpc_token %q, got %q", i, tc.expectedToken, k.grpcTokenFile)
+		}
+	}
+}
+
+func TestKubernetesParseGRPCAddress(t *testing.T) {
+	tests := []struct {
+		input                 string // Corefile data as string
+		expectedAddress       string
+		expectedHealthAddress string
+		shouldErr             bool
+	}{
+		{`kubernetes cluster.local`, ":9288", "", false},
+		{`kubernetes cluster.local {
+			grpcport 9000
+		}`, ":9000", "", false},
+		{`kubernetes cluster.local {
+			grpc_address 127.0.0.1:9288
+		}`, "127.0.0.1:9288", "", false},
+		{`kubernetes cluster.local {
+			grpc_address unix:///var/run/chaos.sock
+			grpc_health_address :9289
+		}`, "unix:///var/run/chaos.sock", ":9289", false},
+		{`kubernetes cluster.local {
+			grpc_address 127.0.0.1
+		}`, "", "", true},
+		{`kubernetes cluster.local {
+			grpcport 9000
+			grpc_address 127.0.0.1:9288
+		}`, "", "", true},
+		{`kubernetes cluster.local {
+			grpc_health_address unix:///var/run/health.sock
+		}`, "", "", true},
+	}
+
+	for i, tc := range tests {
+		c := caddy.NewTestController("dns", tc.input)
+		k, err := kubernetesParse(c)
+		if err != nil && !tc.shouldErr {
+			t.Fatalf("Test %d: Expected no error, got %q", i, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Fatalf("Test %d: Expected error, got none", i)
+		}
+		if err != nil && tc.shouldErr {
+			// input should error
+			continue
+		}
+
+		if address := k.grpcListenAddress(); address != tc.expectedAddress {
+			t.Errorf("Test %d: Expected grpc address %q, got %q", i, tc.expectedAddress, address)
+		}
+		if k.grpcHealthAddress != tc.expectedHealthAddress {
+			t.Errorf("Test %d: Expected grpc health address %q, got %q", i, tc.expectedHealthAddress, k.grpcHealthAddress)
+		}
+	}
+}
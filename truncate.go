@@ -0,0 +1,42 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/coredns/coredns/request"
+
+	"github.com/miekg/dns"
+)
+
+// truncateRetry returns whether the DNS request is the retry over TCP of a truncated answer, which is
+// served with the real answer unless TruncateTCPFail is set
+func (p *PodInfo) truncateRetry(state request.Request) bool {
+	return p.Action == ActionTruncate && state.Proto() == "tcp" && !p.TruncateTCPFail
+}
+
+// truncateChaos answers the DNS request over UDP with the TC bit set, so that the client retries over TCP.
+// The answer is empty, or only has the first record of the real answer if TruncatePartial is set. The
+// DNS requests over TCP reach here only if TruncateTCPFail is set, they fail.
+func (k *Kubernetes) truncateChaos(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	if state.Proto() == "tcp" {
+		return dns.RcodeServerFailure, fmt.Errorf("dns chaos error")
+	}
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = true
+	m.Truncated = true
+
+	if podInfo.TruncatePartial {
+		resp, err := k.Lookup(withChaosLookup(ctx), state, state.QName(), state.QType())
+		if err != nil {
+			log.Warningf("fail to resolve %s for the partial answer: %v", state.QName(), err)
+		} else if resp != nil && len(resp.Answer) != 0 {
+			m.Answer = resp.Answer[:1]
+		}
+	}
+
+	w.WriteMsg(m)
+	return dns.RcodeSuccess, nil
+}
@@ -0,0 +1,58 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+)
+
+func TestTruncateChaos(t *testing.T) {
+	tests := []struct {
+		req               *pb.SetDNSChaosRequest
+		tcp               bool
+		expectedRcode     int
+		expectedTruncated bool
+		expectedAnswers   int
+		expectChaos       bool
+	}{
+		{&pb.SetDNSChaosRequest{Action: ActionTruncate}, false, dns.RcodeSuccess, true, 0, true},
+		{&pb.SetDNSChaosRequest{Action: ActionTruncate, TruncatePartial: true}, false, dns.RcodeSuccess, true, 1, true},
+		// the retry over TCP is served with the real answer
+		{&pb.SetDNSChaosRequest{Action: ActionTruncate}, true, dns.RcodeSuccess, false, 4, false},
+		{&pb.SetDNSChaosRequest{Action: ActionTruncate, TruncateTcpFail: true}, true, dns.RcodeServerFailure, false, 0, true},
+	}
+
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, tc.req)
+		ctx := newChaosLookupContext(t, k)
+
+		m := new(dns.Msg)
+		m.SetQuestion("hdls1.testns.svc.cluster.local.", dns.TypeA)
+		w := dnstest.NewRecorder(&test.ResponseWriter{TCP: tc.tcp})
+
+		rcode, _ := k.ServeDNS(ctx, w, m)
+		if w.Msg == nil {
+			if rcode != tc.expectedRcode {
+				t.Errorf("Test %d: Expected rcode %d, got %d", i, tc.expectedRcode, rcode)
+			}
+		} else {
+			if w.Msg.Rcode != tc.expectedRcode {
+				t.Errorf("Test %d: Expected rcode %d, got %d", i, tc.expectedRcode, w.Msg.Rcode)
+			}
+			if w.Msg.Truncated != tc.expectedTruncated {
+				t.Errorf("Test %d: Expected truncated %v, got %v", i, tc.expectedTruncated, w.Msg.Truncated)
+			}
+			if len(w.Msg.Answer) != tc.expectedAnswers {
+				t.Errorf("Test %d: Expected %d answers, got %v", i, tc.expectedAnswers, w.Msg.Answer)
+			}
+		}
+
+		if hits := k.chaosMap["chaos"].HitCount(); (hits == 1) != tc.expectChaos {
+			t.Errorf("Test %d: Expected chaos %v, got %d hits", i, tc.expectChaos, hits)
+		}
+	}
+}
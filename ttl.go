@@ -0,0 +1,55 @@
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/coredns/coredns/request"
+
+	"github.com/miekg/dns"
+)
+
+// ttlChaos serves the real answer with the TTLs of all the records rewritten to the TTL of the pod. A
+// TTL of 0 defeats the caches of the clients, and a huge one keeps the answer in them long after the
+// experiment is canceled. The minimum TTL of the SOA record is rewritten as well, so that the negative
+// answers are cached in the same way.
+func (k *Kubernetes) ttlChaos(ctx context.Context, w dns.ResponseWriter, r *dns.Msg, state request.Request, podInfo *PodInfo) (int, error) {
+	resp, err := k.Lookup(withChaosLookup(ctx), state, state.QName(), state.QType())
+	if err != nil {
+		return dns.RcodeServerFailure, err
+	}
+	if resp == nil {
+		// no plugin answered the lookup
+		return dns.RcodeServerFailure, nil
+	}
+
+	m := new(dns.Msg)
+	m.SetReply(r)
+	m.Authoritative = resp.Authoritative
+	m.Rcode = resp.Rcode
+	m.Answer = rewriteTTL(resp.Answer, podInfo.TTL)
+	m.Ns = rewriteTTL(resp.Ns, podInfo.TTL)
+	m.Extra = rewriteTTL(resp.Extra, podInfo.TTL)
+
+	w.WriteMsg(m)
+	return dns.RcodeSuccess, nil
+}
+
+// rewriteTTL returns the copies of the records with the TTL rewritten, the OPT records are kept as they
+// don't have a TTL
+func rewriteTTL(records []dns.RR, ttl uint32) []dns.RR {
+	rewritten := make([]dns.RR, 0, len(records))
+	for _, rr := range records {
+		if rr.Header().Rrtype == dns.TypeOPT {
+			rewritten = append(rewritten, rr)
+			continue
+		}
+
+		rr = dns.Copy(rr)
+		rr.Header().Ttl = ttl
+		if soa, ok := rr.(*dns.SOA); ok {
+			soa.Minttl = ttl
+		}
+		rewritten = append(rewritten, rr)
+	}
+	return rewritten
+}
@@ -0,0 +1,84 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chaos-mesh/k8s_dns_chaos/pb"
+	"github.com/coredns/coredns/plugin/pkg/dnstest"
+	"github.com/coredns/coredns/plugin/test"
+
+	"github.com/miekg/dns"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestTTLChaos(t *testing.T) {
+	tests := []struct {
+		ttl           uint32
+		qname         string
+		qtype         uint16
+		expectedRcode int
+	}{
+		{0, "svc1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess},
+		{maxTTL, "hdls1.testns.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess},
+		{86400, "_http._tcp.hdls1.testns.svc.cluster.local.", dns.TypeSRV, dns.RcodeSuccess},
+		// the negative answers are cached for the TTL as well
+		{86400, "missing.testns.svc.cluster.local.", dns.TypeA, dns.RcodeNameError},
+	}
+
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: ActionTTL, Ttl: tc.ttl})
+
+		m := new(dns.Msg)
+		m.SetQuestion(tc.qname, tc.qtype)
+		w := dnstest.NewRecorder(&test.ResponseWriter{})
+		k.ServeDNS(newChaosLookupContext(t, k), w, m)
+
+		if w.Msg == nil || w.Msg.Rcode != tc.expectedRcode {
+			t.Fatalf("Test %d: Expected rcode %d, got %v", i, tc.expectedRcode, w.Msg)
+		}
+		records := append(append(append([]dns.RR{}, w.Msg.Answer...), w.Msg.Ns...), w.Msg.Extra...)
+		if len(records) == 0 {
+			t.Fatalf("Test %d: Expected records, got none", i)
+		}
+		for _, rr := range records {
+			if rr.Header().Ttl != tc.ttl {
+				t.Errorf("Test %d: Expected TTL %d, got %s", i, tc.ttl, rr)
+			}
+			if soa, ok := rr.(*dns.SOA); ok && soa.Minttl != tc.ttl {
+				t.Errorf("Test %d: Expected minimum TTL %d, got %s", i, tc.ttl, rr)
+			}
+		}
+	}
+}
+
+func TestChaosTTL(t *testing.T) {
+	tests := []struct {
+		action      string
+		chaosTTL    *wrapperspb.UInt32Value
+		expectedTTL uint32
+	}{
+		{ActionRandom, nil, defaultChaosTTL},
+		{ActionRandom, wrapperspb.UInt32(0), 0},
+		{ActionRandom, wrapperspb.UInt32(300), 300},
+		{ActionCNAMELoop, wrapperspb.UInt32(1), 1},
+	}
+
+	for i, tc := range tests {
+		k := newChaosTestKubernetes(t, &pb.SetDNSChaosRequest{Action: tc.action, ChaosTtl: tc.chaosTTL})
+
+		m := new(dns.Msg)
+		m.SetQuestion("svc1.testns.svc.cluster.local.", dns.TypeA)
+		w := dnstest.NewRecorder(&test.ResponseWriter{})
+		k.ServeDNS(context.TODO(), w, m)
+
+		if w.Msg == nil || len(w.Msg.Answer) == 0 {
+			t.Fatalf("Test %d: Expected a chaos answer, got %v", i, w.Msg)
+		}
+		for _, rr := range w.Msg.Answer {
+			if rr.Header().Ttl != tc.expectedTTL {
+				t.Errorf("Test %d: Expected TTL %d, got %s", i, tc.expectedTTL, rr)
+			}
+		}
+	}
+}